@@ -9,22 +9,37 @@ import (
 // It accepts a generic registry interface and command arguments.
 type CommandHandler[T any] func(registry T, args []string) error
 
+// OptionsHandler defines the function signature for command handlers that
+// receive the parsed and validated flags and positional arguments.
+type OptionsHandler[T any] func(registry T, opts *Options) error
+
 // Command represents a CLI command with its handler and description.
+//
+// Commands set either Handler, which receives the raw arguments, or Run,
+// which receives the Flags and Args parsed by the dispatcher and answers
+// --help with the generated usage.
 type Command[T any] struct {
 	Name        string
 	Description string
 	Handler     CommandHandler[T]
+
+	Flags []Flag
+	Args  []Arg
+	Run   OptionsHandler[T]
 }
 
 // Dispatcher manages CLI command registration and execution.
+//
+// Commands registered on the dispatcher are top-level commands; use Group
+// to nest commands under namespaces such as "db migrate up".
 type Dispatcher[T any] struct {
-	commands map[string]Command[T]
+	root *Group[T]
 }
 
 // NewDispatcher creates a new CLI command dispatcher.
 func NewDispatcher[T any]() *Dispatcher[T] {
 	return &Dispatcher[T]{
-		commands: make(map[string]Command[T]),
+		root: newGroup[T]("", ""),
 	}
 }
 
@@ -36,23 +51,35 @@ func NewDispatcher[T any]() *Dispatcher[T] {
 // - handler: Function to handle the command
 //
 // Returns:
-// - error: If command name is empty or already registered
+// - error: If command name is empty or already registered, or handler is nil
 func (d *Dispatcher[T]) RegisterCommand(name, description string, handler CommandHandler[T]) error {
-	if name == "" {
-		return errors.New("command name cannot be empty")
-	}
-
-	if _, exists := d.commands[name]; exists {
-		return fmt.Errorf("command '%s' is already registered", name)
-	}
+	return d.root.RegisterCommand(name, description, handler)
+}
 
-	d.commands[name] = Command[T]{
-		Name:        name,
-		Description: description,
-		Handler:     handler,
-	}
+// Register registers a command declared with flags and positional
+// arguments.
+//
+// Parameters:
+// - cmd: The command, with either Handler or Run set
+//
+// Returns:
+// - error: If the name is empty or already registered, or the declarations are invalid
+func (d *Dispatcher[T]) Register(cmd Command[T]) error {
+	return d.root.Register(cmd)
+}
 
-	return nil
+// Group returns the top-level command group with the given name, creating
+// it when it does not exist yet.
+//
+// Parameters:
+// - name: The group name, e.g. "db"
+// - description: Description of the group, kept from the first call if empty
+//
+// Returns:
+// - *Group[T]: The group, to register commands and nested groups on
+// - error: If the name is empty, contains spaces or is taken by a command
+func (d *Dispatcher[T]) Group(name, description string) (*Group[T], error) {
+	return d.root.Group(name, description)
 }
 
 // ExecuteCommand executes a CLI command based on the provided arguments.
@@ -60,8 +87,8 @@ func (d *Dispatcher[T]) RegisterCommand(name, description string, handler Comman
 // Business logic:
 // 1. Logs the command being executed.
 // 2. Validates that at least one argument (the command) is provided.
-// 3. Looks up the command in the registry.
-// 4. If a handler is found, executes it with the remaining arguments.
+// 3. Looks up the command in the registry, descending into groups by name.
+// 4. If a handler is found, executes it with the remaining arguments (parsed into Options for Run handlers).
 // 5. If no handler is found, returns an "unrecognized command" error.
 //
 // Parameters:
@@ -78,48 +105,40 @@ func (d *Dispatcher[T]) ExecuteCommand(registry T, args []string) error {
 		return errors.New("no command provided")
 	}
 
-	command := args[0]
-	remainingArgs := args[1:] // Arguments after the main command
-
-	// Look up the command
-	cmd, found := d.commands[command]
-	if !found {
-		err := fmt.Errorf("unrecognized command: %s", command)
-		fmt.Println(err.Error())
-		return err
-	}
-
-	// Execute the found handler with registry
-	return cmd.Handler(registry, remainingArgs)
+	return d.root.execute(registry, args)
 }
 
 // ListCommands returns a list of all registered commands with their descriptions.
 func (d *Dispatcher[T]) ListCommands() []Command[T] {
-	var commands []Command[T]
-	for _, cmd := range d.commands {
-		commands = append(commands, cmd)
-	}
-	return commands
+	return d.root.ListCommands()
+}
+
+// ListGroups returns the top-level command groups, sorted by name.
+func (d *Dispatcher[T]) ListGroups() []*Group[T] {
+	return d.root.ListGroups()
 }
 
 // GetCommand returns a command by name, or nil if not found.
 func (d *Dispatcher[T]) GetCommand(name string) *Command[T] {
-	if cmd, exists := d.commands[name]; exists {
-		return &cmd
-	}
-	return nil
+	return d.root.GetCommand(name)
+}
+
+// GetGroup returns a top-level group by name, or nil if not found.
+func (d *Dispatcher[T]) GetGroup(name string) *Group[T] {
+	return d.root.GetGroup(name)
 }
 
 // HasCommand checks if a command is registered.
 func (d *Dispatcher[T]) HasCommand(name string) bool {
-	_, exists := d.commands[name]
-	return exists
+	return d.root.HasCommand(name)
+}
+
+// Usage returns the tree of registered groups and commands.
+func (d *Dispatcher[T]) Usage() string {
+	return "Available commands:\n" + d.root.tree()
 }
 
 // PrintUsage prints usage information for all registered commands.
 func (d *Dispatcher[T]) PrintUsage() {
-	fmt.Println("Available commands:")
-	for _, cmd := range d.ListCommands() {
-		fmt.Printf("  %-15s - %s\n", cmd.Name, cmd.Description)
-	}
+	fmt.Print(d.Usage())
 }
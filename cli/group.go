@@ -0,0 +1,274 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Group is a named namespace of commands and nested groups, so that
+// "db migrate up" runs the command "up" of the group "migrate" inside the
+// group "db".
+type Group[T any] struct {
+	Name        string
+	Description string
+
+	path     string
+	commands map[string]Command[T]
+	groups   map[string]*Group[T]
+}
+
+// newGroup creates an empty group. path is the full name used in help
+// output, empty for the dispatcher root.
+func newGroup[T any](name, path string) *Group[T] {
+	return &Group[T]{
+		Name:     name,
+		path:     path,
+		commands: make(map[string]Command[T]),
+		groups:   make(map[string]*Group[T]),
+	}
+}
+
+// Path returns the full name of the group, e.g. "db migrate".
+func (g *Group[T]) Path() string {
+	return g.path
+}
+
+// RegisterCommand registers a new command in the group.
+//
+// Parameters:
+// - name: The command name
+// - description: Description of what the command does
+// - handler: Function to handle the command
+//
+// Returns:
+// - error: If command name is empty or already registered, or handler is nil
+func (g *Group[T]) RegisterCommand(name, description string, handler CommandHandler[T]) error {
+	return g.Register(Command[T]{
+		Name:        name,
+		Description: description,
+		Handler:     handler,
+	})
+}
+
+// Register registers a command declared with flags and positional
+// arguments in the group.
+//
+// Parameters:
+// - cmd: The command, with either Handler or Run set
+//
+// Returns:
+// - error: If the name is empty or already registered, or the declarations are invalid
+func (g *Group[T]) Register(cmd Command[T]) error {
+	if cmd.Name == "" {
+		return errors.New("command name cannot be empty")
+	}
+
+	if _, exists := g.commands[cmd.Name]; exists {
+		return fmt.Errorf("command '%s' is already registered", g.join(cmd.Name))
+	}
+
+	if _, exists := g.groups[cmd.Name]; exists {
+		return fmt.Errorf("command '%s' is already registered as a group", g.join(cmd.Name))
+	}
+
+	if cmd.Handler != nil && cmd.Run != nil {
+		return fmt.Errorf("command '%s' cannot set both Handler and Run", g.join(cmd.Name))
+	}
+
+	if cmd.Handler == nil && cmd.Run == nil {
+		return fmt.Errorf("command '%s' has no Handler or Run", g.join(cmd.Name))
+	}
+
+	if cmd.Run == nil && (len(cmd.Flags) > 0 || len(cmd.Args) > 0) {
+		return fmt.Errorf("command '%s' declares options but has no Run handler", g.join(cmd.Name))
+	}
+
+	if err := cmd.validateOptions(); err != nil {
+		return err
+	}
+
+	g.commands[cmd.Name] = cmd
+
+	return nil
+}
+
+// Group returns the nested group with the given name, creating it when it
+// does not exist yet.
+//
+// Parameters:
+// - name: The group name, e.g. "migrate"
+// - description: Description of the group, kept from the first call if empty
+//
+// Returns:
+// - *Group[T]: The group, to register commands and nested groups on
+// - error: If the name is empty, contains spaces or is taken by a command
+func (g *Group[T]) Group(name, description string) (*Group[T], error) {
+	if name == "" {
+		return nil, errors.New("group name cannot be empty")
+	}
+
+	if strings.ContainsAny(name, " \t\n") || strings.HasPrefix(name, "-") {
+		return nil, fmt.Errorf("group name '%s' is invalid", name)
+	}
+
+	if _, exists := g.commands[name]; exists {
+		return nil, fmt.Errorf("group '%s' is already registered as a command", g.join(name))
+	}
+
+	group, exists := g.groups[name]
+	if !exists {
+		group = newGroup[T](name, g.join(name))
+		g.groups[name] = group
+	}
+
+	if description != "" {
+		group.Description = description
+	}
+
+	return group, nil
+}
+
+// ListCommands returns the commands of the group, without those of nested
+// groups.
+func (g *Group[T]) ListCommands() []Command[T] {
+	var commands []Command[T]
+	for _, cmd := range g.commands {
+		commands = append(commands, cmd)
+	}
+	return commands
+}
+
+// ListGroups returns the nested groups, sorted by name.
+func (g *Group[T]) ListGroups() []*Group[T] {
+	groups := make([]*Group[T], 0, len(g.groups))
+	for _, group := range g.groups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+	return groups
+}
+
+// GetCommand returns a command of the group by name, or nil if not found.
+func (g *Group[T]) GetCommand(name string) *Command[T] {
+	if cmd, exists := g.commands[name]; exists {
+		return &cmd
+	}
+	return nil
+}
+
+// GetGroup returns a nested group by name, or nil if not found.
+func (g *Group[T]) GetGroup(name string) *Group[T] {
+	return g.groups[name]
+}
+
+// HasCommand checks if a command is registered in the group.
+func (g *Group[T]) HasCommand(name string) bool {
+	_, exists := g.commands[name]
+	return exists
+}
+
+// Help returns the usage of the group with the tree of its commands.
+func (g *Group[T]) Help() string {
+	builder := &strings.Builder{}
+
+	fmt.Fprintf(builder, "Usage: %s <command> [args]\n", g.path)
+	if g.Description != "" {
+		fmt.Fprintln(builder)
+		fmt.Fprintln(builder, g.Description)
+	}
+	fmt.Fprintln(builder)
+	fmt.Fprintln(builder, "Commands:")
+	builder.WriteString(g.tree())
+
+	return builder.String()
+}
+
+// execute resolves args against the group, descending into nested groups,
+// and runs the command found.
+func (g *Group[T]) execute(registry T, args []string) error {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		fmt.Print(g.Help())
+		if len(args) == 0 {
+			return fmt.Errorf("no command provided for group: %s", g.path)
+		}
+		return nil
+	}
+
+	name := args[0]
+	remainingArgs := args[1:] // Arguments after the command or group
+
+	if group, found := g.groups[name]; found {
+		return group.execute(registry, remainingArgs)
+	}
+
+	// Look up the command
+	cmd, found := g.commands[name]
+	if !found {
+		err := fmt.Errorf("unrecognized command: %s", g.join(name))
+		fmt.Println(err.Error())
+		return err
+	}
+
+	if cmd.Run == nil {
+		// Execute the found handler with registry
+		return cmd.Handler(registry, remainingArgs)
+	}
+
+	// Parse and validate the declared options, --help prints the usage
+	opts, err := cmd.Parse(remainingArgs)
+	if errors.Is(err, ErrHelp) {
+		fmt.Print(cmd.help(g.join(name)))
+		return nil
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		fmt.Print(cmd.help(g.join(name)))
+		return err
+	}
+
+	return cmd.Run(registry, opts)
+}
+
+// tree lists the commands and nested groups, sorted by name, one per line
+// and indented by depth.
+func (g *Group[T]) tree() string {
+	builder := &strings.Builder{}
+	g.writeTree(builder, 1)
+	return builder.String()
+}
+
+// writeTree writes the entries of the group indented by depth levels.
+func (g *Group[T]) writeTree(builder *strings.Builder, depth int) {
+	names := make([]string, 0, len(g.commands)+len(g.groups))
+	for name := range g.commands {
+		names = append(names, name)
+	}
+	for name := range g.groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	indent := strings.Repeat("  ", depth)
+	width := max(17-len(indent), 1)
+
+	for _, name := range names {
+		if group, isGroup := g.groups[name]; isGroup {
+			fmt.Fprintf(builder, "%s%-*s - %s\n", indent, width, name+"/", group.Description)
+			group.writeTree(builder, depth+1)
+			continue
+		}
+		fmt.Fprintf(builder, "%s%-*s - %s\n", indent, width, name, g.commands[name].Description)
+	}
+}
+
+// join returns the full name of a child of the group.
+func (g *Group[T]) join(name string) string {
+	if g.path == "" {
+		return name
+	}
+	return g.path + " " + name
+}
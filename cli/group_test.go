@@ -0,0 +1,139 @@
+package cli_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dracory/base/cli"
+)
+
+func TestGroupExecuteCommand(t *testing.T) {
+	dispatcher := cli.NewDispatcher[MockRegistry]()
+	registry := MockRegistry{name: "test"}
+
+	db, err := dispatcher.Group("db", "Database tasks")
+	if err != nil {
+		t.Fatal("Failed to create group:", err)
+	}
+	migrate, err := db.Group("migrate", "Schema migrations")
+	if err != nil {
+		t.Fatal("Failed to create nested group:", err)
+	}
+
+	var receivedArgs []string
+	err = migrate.RegisterCommand("up", "Apply migrations", func(registry MockRegistry, args []string) error {
+		receivedArgs = args
+		return nil
+	})
+	if err != nil {
+		t.Fatal("Failed to register command:", err)
+	}
+
+	if err := dispatcher.ExecuteCommand(registry, []string{"db", "migrate", "up", "--step", "2"}); err != nil {
+		t.Fatal("Expected success, got error:", err)
+	}
+	if strings.Join(receivedArgs, " ") != "--step 2" {
+		t.Fatalf("Expected [--step 2], got %v", receivedArgs)
+	}
+
+	err = dispatcher.ExecuteCommand(registry, []string{"db", "migrate", "sideways"})
+	if err == nil || err.Error() != "unrecognized command: db migrate sideways" {
+		t.Fatalf("Expected 'unrecognized command: db migrate sideways', got '%v'", err)
+	}
+
+	err = dispatcher.ExecuteCommand(registry, []string{"db"})
+	if err == nil || err.Error() != "no command provided for group: db" {
+		t.Fatalf("Expected 'no command provided for group: db', got '%v'", err)
+	}
+
+	if err := dispatcher.ExecuteCommand(registry, []string{"db", "--help"}); err != nil {
+		t.Fatal("Expected group help to succeed, got error:", err)
+	}
+}
+
+func TestGroupRegistration(t *testing.T) {
+	dispatcher := cli.NewDispatcher[MockRegistry]()
+
+	if err := dispatcher.RegisterCommand("cache", "Cache command", MockCommandHandler(nil)); err != nil {
+		t.Fatal("Failed to register command:", err)
+	}
+	if _, err := dispatcher.Group("cache", "Cache tasks"); err == nil {
+		t.Fatal("Expected error when a group takes the name of a command")
+	}
+	if _, err := dispatcher.Group("", "Empty"); err == nil {
+		t.Fatal("Expected error when creating group with empty name")
+	}
+
+	db, _ := dispatcher.Group("db", "Database tasks")
+	again, err := dispatcher.Group("db", "")
+	if err != nil || again != db || again.Description != "Database tasks" {
+		t.Fatal("Expected the existing group to be returned with its description")
+	}
+	if err := dispatcher.RegisterCommand("db", "Clashing command", MockCommandHandler(nil)); err == nil {
+		t.Fatal("Expected error when a command takes the name of a group")
+	}
+
+	migrate, _ := db.Group("migrate", "Schema migrations")
+	if migrate.Path() != "db migrate" {
+		t.Fatalf("Expected path 'db migrate', got '%s'", migrate.Path())
+	}
+	if dispatcher.GetGroup("db").GetGroup("migrate") != migrate {
+		t.Fatal("Expected nested group to be found")
+	}
+	if len(dispatcher.ListGroups()) != 1 || len(dispatcher.ListCommands()) != 1 {
+		t.Fatalf("Expected 1 group and 1 command, got %d and %d", len(dispatcher.ListGroups()), len(dispatcher.ListCommands()))
+	}
+}
+
+func TestDispatcherUsageTree(t *testing.T) {
+	dispatcher := cli.NewDispatcher[MockRegistry]()
+	dispatcher.RegisterCommand("serve", "Start the server", MockCommandHandler(nil))
+
+	db, _ := dispatcher.Group("db", "Database tasks")
+	db.RegisterCommand("seed", "Seed the database", MockCommandHandler(nil))
+	migrate, _ := db.Group("migrate", "Schema migrations")
+	migrate.RegisterCommand("up", "Apply migrations", MockCommandHandler(nil))
+	migrate.RegisterCommand("down", "Roll back migrations", MockCommandHandler(nil))
+
+	expected := "Available commands:\n" +
+		"  db/             - Database tasks\n" +
+		"    migrate/      - Schema migrations\n" +
+		"      down        - Roll back migrations\n" +
+		"      up          - Apply migrations\n" +
+		"    seed          - Seed the database\n" +
+		"  serve           - Start the server\n"
+
+	if usage := dispatcher.Usage(); usage != expected {
+		t.Fatalf("Expected usage:\n%s\ngot:\n%s", expected, usage)
+	}
+
+	help := migrate.Help()
+	if !strings.HasPrefix(help, "Usage: db migrate <command> [args]\n\nSchema migrations\n") {
+		t.Fatalf("Unexpected group help:\n%s", help)
+	}
+}
+
+func TestGroupCommandHelpPath(t *testing.T) {
+	dispatcher := cli.NewDispatcher[MockRegistry]()
+	registry := MockRegistry{name: "test"}
+
+	cache, _ := dispatcher.Group("cache", "Cache tasks")
+	calls := 0
+	err := cache.Register(cli.Command[MockRegistry]{
+		Name:  "clear",
+		Flags: []cli.Flag{{Name: "all", Type: cli.TypeBool}},
+		Run: func(registry MockRegistry, opts *cli.Options) error {
+			if opts.Bool("all") {
+				calls++
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal("Failed to register command:", err)
+	}
+
+	if err := dispatcher.ExecuteCommand(registry, []string{"cache", "clear", "--all"}); err != nil || calls != 1 {
+		t.Fatalf("Expected one call with --all, got %d calls and error %v", calls, err)
+	}
+}
@@ -0,0 +1,510 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// ErrHelp is returned by Command.Parse when --help or -h is given.
+var ErrHelp = errors.New("help requested")
+
+// ValueType is the type of a flag or positional argument value.
+type ValueType string
+
+// Value types understood by the parser. The zero value is TypeString.
+const (
+	TypeString   ValueType = ""
+	TypeInt      ValueType = "int"
+	TypeBool     ValueType = "bool"
+	TypeDuration ValueType = "duration"
+)
+
+// Flag declares a named option such as --port 8080 or -p=8080.
+type Flag struct {
+	// Name is the long name, used as --name
+	Name string
+
+	// Short is an optional one letter alias, used as -s
+	Short string
+
+	// Type is the value type, TypeString if empty. Boolean flags need no
+	// value: --verbose is the same as --verbose=true
+	Type ValueType
+
+	// Usage describes the flag in the help output
+	Usage string
+
+	// Default is used when the flag is neither given nor set in Env,
+	// written as on the command line. Repeated defaults are comma separated
+	Default string
+
+	// Env is an optional environment variable read when the flag is not
+	// given. Repeated values are comma separated
+	Env string
+
+	// Required fails parsing when the flag is neither given nor set in Env
+	Required bool
+
+	// Repeated collects every occurrence instead of keeping the last one
+	Repeated bool
+}
+
+// Arg declares a positional argument.
+type Arg struct {
+	// Name identifies the argument in Options and the help output
+	Name string
+
+	// Type is the value type, TypeString if empty
+	Type ValueType
+
+	// Usage describes the argument in the help output
+	Usage string
+
+	// Default is used when the argument is not given
+	Default string
+
+	// Required fails parsing when the argument is not given. Required
+	// arguments must come before optional ones
+	Required bool
+
+	// Repeated collects the remaining arguments, only allowed on the last
+	Repeated bool
+}
+
+// Options holds the parsed flags and positional arguments of a command.
+type Options struct {
+	values map[string]any
+	set    map[string]bool
+	args   []string
+}
+
+func newOptions() *Options {
+	return &Options{
+		values: map[string]any{},
+		set:    map[string]bool{},
+		args:   []string{},
+	}
+}
+
+// String returns the value of a string flag or argument.
+func (o *Options) String(name string) string {
+	value, _ := o.values[name].(string)
+	return value
+}
+
+// Int returns the value of an int flag or argument.
+func (o *Options) Int(name string) int {
+	value, _ := o.values[name].(int)
+	return value
+}
+
+// Bool returns the value of a bool flag or argument.
+func (o *Options) Bool(name string) bool {
+	value, _ := o.values[name].(bool)
+	return value
+}
+
+// Duration returns the value of a duration flag or argument.
+func (o *Options) Duration(name string) time.Duration {
+	value, _ := o.values[name].(time.Duration)
+	return value
+}
+
+// Strings returns the values of a repeated string flag or argument.
+func (o *Options) Strings(name string) []string {
+	value, _ := o.values[name].([]string)
+	return value
+}
+
+// Ints returns the values of a repeated int flag or argument.
+func (o *Options) Ints(name string) []int {
+	value, _ := o.values[name].([]int)
+	return value
+}
+
+// Durations returns the values of a repeated duration flag or argument.
+func (o *Options) Durations(name string) []time.Duration {
+	value, _ := o.values[name].([]time.Duration)
+	return value
+}
+
+// IsSet reports whether the flag or argument was given on the command line
+// or, for flags, in its environment variable. Defaults do not count.
+func (o *Options) IsSet(name string) bool {
+	return o.set[name]
+}
+
+// Args returns the raw positional arguments.
+func (o *Options) Args() []string {
+	return o.args
+}
+
+// store parses raw and stores it under name, appending when repeated.
+func (o *Options) store(name string, valueType ValueType, repeated bool, raw string) error {
+	value, err := parseValue(valueType, raw)
+	if err != nil {
+		return err
+	}
+	if !repeated {
+		o.values[name] = value
+		return nil
+	}
+
+	switch v := value.(type) {
+	case string:
+		existing, _ := o.values[name].([]string)
+		o.values[name] = append(existing, v)
+	case int:
+		existing, _ := o.values[name].([]int)
+		o.values[name] = append(existing, v)
+	case time.Duration:
+		existing, _ := o.values[name].([]time.Duration)
+		o.values[name] = append(existing, v)
+	}
+	return nil
+}
+
+// storeList stores a comma separated value, as found in defaults and
+// environment variables.
+func (o *Options) storeList(name string, valueType ValueType, repeated bool, raw string) error {
+	if !repeated {
+		return o.store(name, valueType, false, raw)
+	}
+	for _, item := range strings.Split(raw, ",") {
+		if err := o.store(name, valueType, true, strings.TrimSpace(item)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseValue converts raw to the Go type of valueType.
+func parseValue(valueType ValueType, raw string) (any, error) {
+	switch valueType {
+	case TypeInt:
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid int value %q", raw)
+		}
+		return value, nil
+	case TypeBool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bool value %q", raw)
+		}
+		return value, nil
+	case TypeDuration:
+		value, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration value %q", raw)
+		}
+		return value, nil
+	default:
+		return raw, nil
+	}
+}
+
+// validateOptions checks the flag and argument declarations of a command.
+func (c Command[T]) validateOptions() error {
+	names := map[string]bool{}
+	shorts := map[string]bool{}
+	known := []ValueType{TypeString, TypeInt, TypeBool, TypeDuration}
+
+	for _, flag := range c.Flags {
+		if flag.Name == "" || strings.HasPrefix(flag.Name, "-") {
+			return fmt.Errorf("command '%s' has an invalid flag name '%s'", c.Name, flag.Name)
+		}
+		if flag.Name == "help" || flag.Short == "h" {
+			return fmt.Errorf("command '%s' cannot redeclare the help flag", c.Name)
+		}
+		if names[flag.Name] {
+			return fmt.Errorf("command '%s' declares '%s' twice", c.Name, flag.Name)
+		}
+		names[flag.Name] = true
+
+		if flag.Short != "" {
+			if len(flag.Short) != 1 || shorts[flag.Short] {
+				return fmt.Errorf("command '%s' has an invalid short name '%s' for flag '%s'", c.Name, flag.Short, flag.Name)
+			}
+			shorts[flag.Short] = true
+		}
+		if !slices.Contains(known, flag.Type) {
+			return fmt.Errorf("command '%s' flag '%s' has unknown type '%s'", c.Name, flag.Name, flag.Type)
+		}
+		if flag.Repeated && flag.Type == TypeBool {
+			return fmt.Errorf("command '%s' flag '%s' cannot be a repeated bool", c.Name, flag.Name)
+		}
+		if flag.Default != "" {
+			if err := newOptions().storeList(flag.Name, flag.Type, flag.Repeated, flag.Default); err != nil {
+				return fmt.Errorf("command '%s' flag '%s' has an invalid default: %w", c.Name, flag.Name, err)
+			}
+		}
+	}
+
+	optional := false
+	for i, arg := range c.Args {
+		if arg.Name == "" {
+			return fmt.Errorf("command '%s' has an argument without a name", c.Name)
+		}
+		if names[arg.Name] {
+			return fmt.Errorf("command '%s' declares '%s' twice", c.Name, arg.Name)
+		}
+		names[arg.Name] = true
+
+		if !slices.Contains(known, arg.Type) {
+			return fmt.Errorf("command '%s' argument '%s' has unknown type '%s'", c.Name, arg.Name, arg.Type)
+		}
+		if arg.Repeated && (i != len(c.Args)-1 || arg.Type == TypeBool) {
+			return fmt.Errorf("command '%s' argument '%s' can only be repeated when it is the last and not a bool", c.Name, arg.Name)
+		}
+		if arg.Required && optional {
+			return fmt.Errorf("command '%s' required argument '%s' follows an optional one", c.Name, arg.Name)
+		}
+		optional = optional || !arg.Required
+		if arg.Default != "" {
+			if err := newOptions().storeList(arg.Name, arg.Type, arg.Repeated, arg.Default); err != nil {
+				return fmt.Errorf("command '%s' argument '%s' has an invalid default: %w", c.Name, arg.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Parse parses args against the declared flags and positional arguments.
+// Flags may appear before, between or after positional arguments, and "--"
+// ends flag parsing. Long names take exactly two dashes and short names
+// exactly one, as shown by the generated help. A negative number such as
+// "-1" that matches no declared flag is a positional argument. Unset flags
+// fall back to their environment variable, then to their default.
+//
+// Returns:
+// - *Options: The parsed values
+// - error: ErrHelp when --help or -h is given, or the first invalid, unknown or missing value
+func (c Command[T]) Parse(args []string) (*Options, error) {
+	opts := newOptions()
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			opts.args = append(opts.args, args[i+1:]...)
+			break
+		}
+		if arg == "--help" || arg == "-h" {
+			return nil, ErrHelp
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			opts.args = append(opts.args, arg)
+			continue
+		}
+
+		token, value, hasValue := strings.Cut(arg, "=")
+		flag, found := c.flag(token)
+		if !found && isNumber(arg) {
+			opts.args = append(opts.args, arg)
+			continue
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown flag: %s", arg)
+		}
+
+		if !hasValue && flag.Type == TypeBool {
+			value = "true"
+		} else if !hasValue {
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("flag --%s needs a value", flag.Name)
+			}
+			i++
+			value = args[i]
+		}
+
+		if err := opts.store(flag.Name, flag.Type, flag.Repeated, value); err != nil {
+			return nil, fmt.Errorf("flag --%s: %w", flag.Name, err)
+		}
+		opts.set[flag.Name] = true
+	}
+
+	for _, flag := range c.Flags {
+		if opts.set[flag.Name] {
+			continue
+		}
+		if env := os.Getenv(flag.Env); flag.Env != "" && env != "" {
+			if err := opts.storeList(flag.Name, flag.Type, flag.Repeated, env); err != nil {
+				return nil, fmt.Errorf("flag --%s from %s: %w", flag.Name, flag.Env, err)
+			}
+			opts.set[flag.Name] = true
+			continue
+		}
+		if flag.Required {
+			return nil, fmt.Errorf("missing required flag: --%s", flag.Name)
+		}
+		if flag.Default != "" {
+			if err := opts.storeList(flag.Name, flag.Type, flag.Repeated, flag.Default); err != nil {
+				return nil, fmt.Errorf("flag --%s: %w", flag.Name, err)
+			}
+		}
+	}
+
+	if err := c.parseArgs(opts); err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
+
+// parseArgs assigns the positional arguments in opts.args to the declared
+// arguments.
+func (c Command[T]) parseArgs(opts *Options) error {
+	for i, arg := range c.Args {
+		var given []string
+		switch {
+		case arg.Repeated && i < len(opts.args):
+			given = opts.args[i:]
+		case i < len(opts.args):
+			given = opts.args[i : i+1]
+		}
+
+		if len(given) == 0 {
+			if arg.Required {
+				return fmt.Errorf("missing required argument: %s", arg.Name)
+			}
+			if arg.Default != "" {
+				if err := opts.storeList(arg.Name, arg.Type, arg.Repeated, arg.Default); err != nil {
+					return fmt.Errorf("argument %s: %w", arg.Name, err)
+				}
+			}
+			continue
+		}
+
+		for _, value := range given {
+			if err := opts.store(arg.Name, arg.Type, arg.Repeated, value); err != nil {
+				return fmt.Errorf("argument %s: %w", arg.Name, err)
+			}
+		}
+		opts.set[arg.Name] = true
+	}
+
+	last := len(c.Args) - 1
+	if len(opts.args) > len(c.Args) && (last < 0 || !c.Args[last].Repeated) {
+		return fmt.Errorf("unexpected argument: %s", opts.args[len(c.Args)])
+	}
+
+	return nil
+}
+
+// flag finds the declared flag named by token, which is "--" followed by
+// the long name or "-" followed by the one letter short name.
+func (c Command[T]) flag(token string) (Flag, bool) {
+	long, isLong := strings.CutPrefix(token, "--")
+	short, isShort := strings.CutPrefix(token, "-")
+	isShort = !isLong && isShort && len(short) == 1
+
+	for _, flag := range c.Flags {
+		if (isLong && flag.Name == long) || (isShort && flag.Short == short) {
+			return flag, true
+		}
+	}
+	return Flag{}, false
+}
+
+// Help returns the usage of the command generated from its declarations.
+func (c Command[T]) Help() string {
+	return c.help(c.Name)
+}
+
+// help renders the usage with path as the command name.
+func (c Command[T]) help(path string) string {
+	builder := &strings.Builder{}
+
+	usage := "Usage: " + path + " [flags]"
+	for _, arg := range c.Args {
+		name := arg.Name
+		if arg.Repeated {
+			name += "..."
+		}
+		if arg.Required {
+			usage += " <" + name + ">"
+		} else {
+			usage += " [" + name + "]"
+		}
+	}
+	fmt.Fprintln(builder, usage)
+
+	if c.Description != "" {
+		fmt.Fprintln(builder)
+		fmt.Fprintln(builder, c.Description)
+	}
+
+	writer := tabwriter.NewWriter(builder, 0, 0, 2, ' ', 0)
+
+	if len(c.Args) > 0 {
+		fmt.Fprintln(writer)
+		fmt.Fprintln(writer, "Arguments:")
+		for _, arg := range c.Args {
+			fmt.Fprintf(writer, "  %s\t%s\t%s\n", arg.Name, arg.Type.label(), optionDetails(arg.Usage, arg.Default, "", arg.Required, arg.Repeated))
+		}
+	}
+
+	fmt.Fprintln(writer)
+	fmt.Fprintln(writer, "Flags:")
+	for _, flag := range c.Flags {
+		names := "    --" + flag.Name
+		if flag.Short != "" {
+			names = "-" + flag.Short + ", --" + flag.Name
+		}
+		fmt.Fprintf(writer, "  %s\t%s\t%s\n", names, flag.Type.label(), optionDetails(flag.Usage, flag.Default, flag.Env, flag.Required, flag.Repeated))
+	}
+	fmt.Fprintf(writer, "  %s\t%s\t%s\n", "-h, --help", "", "Show this help")
+	writer.Flush()
+
+	return builder.String()
+}
+
+// label returns the name of the type shown in the help output. Boolean
+// flags take no value, so they show none.
+func (t ValueType) label() string {
+	switch t {
+	case TypeString:
+		return "string"
+	case TypeBool:
+		return ""
+	default:
+		return string(t)
+	}
+}
+
+// optionDetails describes a flag or argument in the help output.
+func optionDetails(usage, defaultValue, env string, required, repeated bool) string {
+	details := []string{}
+	if required {
+		details = append(details, "required")
+	}
+	if repeated {
+		details = append(details, "repeated")
+	}
+	if defaultValue != "" {
+		details = append(details, "default "+defaultValue)
+	}
+	if env != "" {
+		details = append(details, "env "+env)
+	}
+	if len(details) == 0 {
+		return usage
+	}
+	return strings.TrimSpace(usage + " (" + strings.Join(details, ", ") + ")")
+}
+
+// isNumber reports whether arg is a negative number, e.g. "-1" or "-0.5"
+func isNumber(arg string) bool {
+	if len(arg) < 2 || !strings.ContainsRune("0123456789.", rune(arg[1])) {
+		return false
+	}
+	_, err := strconv.ParseFloat(arg, 64)
+	return err == nil
+}
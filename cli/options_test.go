@@ -0,0 +1,199 @@
+package cli_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dracory/base/cli"
+)
+
+func serveCommand(run cli.OptionsHandler[MockRegistry]) cli.Command[MockRegistry] {
+	return cli.Command[MockRegistry]{
+		Name:        "serve",
+		Description: "Starts the server",
+		Flags: []cli.Flag{
+			{Name: "port", Short: "p", Type: cli.TypeInt, Usage: "Port to listen on", Default: "8080", Env: "TEST_CLI_PORT"},
+			{Name: "verbose", Short: "v", Type: cli.TypeBool, Usage: "Enable verbose logging"},
+			{Name: "timeout", Type: cli.TypeDuration, Default: "5s"},
+			{Name: "tag", Usage: "Tag to apply", Repeated: true},
+			{Name: "env", Usage: "Environment", Required: true},
+		},
+		Args: []cli.Arg{
+			{Name: "addr", Usage: "Address to bind", Required: true},
+			{Name: "files", Usage: "Files to serve", Repeated: true},
+		},
+		Run: run,
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	cmd := serveCommand(nil)
+
+	opts, err := cmd.Parse([]string{"localhost", "--env=prod", "-p", "9000", "-v", "--tag", "a", "--tag=b", "index.html", "--", "--raw"})
+	if err != nil {
+		t.Fatal("Expected success, got error:", err)
+	}
+
+	if opts.Int("port") != 9000 || !opts.Bool("verbose") || opts.String("env") != "prod" {
+		t.Fatalf("Unexpected flag values: port=%d verbose=%v env=%s", opts.Int("port"), opts.Bool("verbose"), opts.String("env"))
+	}
+
+	if opts.Duration("timeout") != 5*time.Second || opts.IsSet("timeout") {
+		t.Fatalf("Expected default timeout of 5s not marked as set, got %v", opts.Duration("timeout"))
+	}
+
+	if strings.Join(opts.Strings("tag"), ",") != "a,b" {
+		t.Fatalf("Expected tags [a b], got %v", opts.Strings("tag"))
+	}
+
+	if opts.String("addr") != "localhost" || strings.Join(opts.Strings("files"), ",") != "index.html,--raw" {
+		t.Fatalf("Unexpected arguments: addr=%s files=%v", opts.String("addr"), opts.Strings("files"))
+	}
+}
+
+func TestParseOptionsEnvFallback(t *testing.T) {
+	t.Setenv("TEST_CLI_PORT", "7000")
+	cmd := serveCommand(nil)
+
+	opts, err := cmd.Parse([]string{"--env", "dev", "localhost"})
+	if err != nil {
+		t.Fatal("Expected success, got error:", err)
+	}
+	if opts.Int("port") != 7000 || !opts.IsSet("port") {
+		t.Fatalf("Expected port 7000 from env, got %d", opts.Int("port"))
+	}
+
+	opts, err = cmd.Parse([]string{"--env", "dev", "--port", "1", "localhost"})
+	if err != nil {
+		t.Fatal("Expected success, got error:", err)
+	}
+	if opts.Int("port") != 1 {
+		t.Fatalf("Expected command line to win over env, got %d", opts.Int("port"))
+	}
+}
+
+func TestParseOptionsErrors(t *testing.T) {
+	cmd := serveCommand(nil)
+
+	cases := map[string][]string{
+		"missing required flag: --env":       {"localhost"},
+		"missing required argument: addr":    {"--env", "dev"},
+		"unknown flag: --nope":               {"--env", "dev", "--nope", "localhost"},
+		`flag --port: invalid int value "x"`: {"--env", "dev", "--port", "x", "localhost"},
+		"flag --env needs a value":           {"localhost", "--env"},
+		"unknown flag: ---env":               {"---env", "dev", "localhost"},
+		"unknown flag: -env":                 {"-env", "dev", "localhost"},
+		"unknown flag: --p":                  {"--env", "dev", "--p", "9000", "localhost"},
+		"unknown flag: -port=9000":           {"--env", "dev", "-port=9000", "localhost"},
+	}
+
+	for expected, args := range cases {
+		_, err := cmd.Parse(args)
+		if err == nil || err.Error() != expected {
+			t.Fatalf("Expected '%s' for %v, got '%v'", expected, args, err)
+		}
+	}
+
+	_, err := cmd.Parse([]string{"--help"})
+	if !errors.Is(err, cli.ErrHelp) {
+		t.Fatal("Expected ErrHelp, got:", err)
+	}
+
+	shift := cli.Command[MockRegistry]{Name: "shift", Args: []cli.Arg{{Name: "steps", Type: cli.TypeInt}}}
+	opts, err := shift.Parse([]string{"-1"})
+	if err != nil || opts.Int("steps") != -1 {
+		t.Fatal("Expected -1 to be parsed as a positional argument, got:", err)
+	}
+	if _, err := shift.Parse([]string{"-x"}); err == nil || err.Error() != "unknown flag: -x" {
+		t.Fatal("Expected unknown flag error, got:", err)
+	}
+
+	single := cli.Command[MockRegistry]{Name: "one", Args: []cli.Arg{{Name: "name"}}}
+	if _, err := single.Parse([]string{"a", "b"}); err == nil || err.Error() != "unexpected argument: b" {
+		t.Fatal("Expected unexpected argument error, got:", err)
+	}
+}
+
+func TestRegisterInvalidOptions(t *testing.T) {
+	run := func(registry MockRegistry, opts *cli.Options) error { return nil }
+
+	cases := []cli.Command[MockRegistry]{
+		{Name: "a", Flags: []cli.Flag{{Name: "x"}}},
+		{Name: "b", Flags: []cli.Flag{{Name: "x"}, {Name: "x"}}, Run: run},
+		{Name: "c", Flags: []cli.Flag{{Name: "n", Type: cli.TypeInt, Default: "many"}}, Run: run},
+		{Name: "d", Flags: []cli.Flag{{Name: "t", Type: "float"}}, Run: run},
+		{Name: "e", Args: []cli.Arg{{Name: "opt"}, {Name: "req", Required: true}}, Run: run},
+		{Name: "f", Args: []cli.Arg{{Name: "rest", Repeated: true}, {Name: "last"}}, Run: run},
+		{Name: "g", Flags: []cli.Flag{{Name: "help"}}, Run: run},
+		{Name: "h", Description: "No handler"},
+	}
+
+	for _, cmd := range cases {
+		dispatcher := cli.NewDispatcher[MockRegistry]()
+		if err := dispatcher.Register(cmd); err == nil {
+			t.Fatalf("Expected error registering command '%s'", cmd.Name)
+		}
+	}
+
+	dispatcher := cli.NewDispatcher[MockRegistry]()
+	if err := dispatcher.RegisterCommand("nil", "Nil handler", nil); err == nil {
+		t.Fatal("Expected error registering a command with a nil handler")
+	}
+}
+
+func TestExecuteCommandWithOptions(t *testing.T) {
+	dispatcher := cli.NewDispatcher[MockRegistry]()
+	registry := MockRegistry{name: "test"}
+
+	calls := 0
+	var port int
+	err := dispatcher.Register(serveCommand(func(registry MockRegistry, opts *cli.Options) error {
+		calls++
+		port = opts.Int("port")
+		return nil
+	}))
+	if err != nil {
+		t.Fatal("Failed to register command:", err)
+	}
+
+	if err := dispatcher.ExecuteCommand(registry, []string{"serve", "--env", "dev", "localhost"}); err != nil {
+		t.Fatal("Expected success, got error:", err)
+	}
+	if calls != 1 || port != 8080 {
+		t.Fatalf("Expected one call with default port, got %d calls and port %d", calls, port)
+	}
+
+	if err := dispatcher.ExecuteCommand(registry, []string{"serve", "--help"}); err != nil {
+		t.Fatal("Expected help to succeed, got error:", err)
+	}
+
+	if err := dispatcher.ExecuteCommand(registry, []string{"serve"}); err == nil {
+		t.Fatal("Expected error for missing required options")
+	}
+
+	if calls != 1 {
+		t.Fatalf("Expected handler not to run for help or invalid options, got %d calls", calls)
+	}
+}
+
+func TestCommandHelp(t *testing.T) {
+	help := serveCommand(nil).Help()
+
+	expected := []string{
+		"Usage: serve [flags] <addr> [files...]",
+		"Starts the server",
+		"addr",
+		"-p, --port",
+		"Port to listen on (default 8080, env TEST_CLI_PORT)",
+		"Environment (required)",
+		"Tag to apply (repeated)",
+		"-h, --help",
+	}
+	for _, text := range expected {
+		if !strings.Contains(help, text) {
+			t.Fatalf("Expected help to contain '%s', got:\n%s", text, help)
+		}
+	}
+}
@@ -1,12 +1,56 @@
 package config
 
-import "strings"
+import (
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
 
 // LoadAccumulator centralizes validation error collection while building a
 // configuration instance. Helper methods mirror the existing RequireString
 // and RequireWhen primitives so callers stay concise.
+//
+// The zero value reads the process environment; use NewLookupAccumulator to
+// validate values from another place, such as a loaded ConfigInterface.
 type LoadAccumulator struct {
-	errs []error
+	errs   []error
+	keys   []string
+	lookup func(key string) string
+}
+
+// NewLookupAccumulator creates an accumulator whose Must* helpers read
+// values through lookup instead of the process environment.
+//
+// Example:
+//
+//	acc := config.NewLookupAccumulator(config.ConfigLookup(cfg))
+//	port := acc.MustPort("APP_PORT", "required by the HTTP server")
+func NewLookupAccumulator(lookup func(key string) string) *LoadAccumulator {
+	return &LoadAccumulator{lookup: lookup}
+}
+
+// ConfigLookup returns a lookup function reading the values of cfg as
+// strings, for NewLookupAccumulator and Rule.Evaluate. Missing keys and
+// values that cannot be converted, such as nested maps, read as "".
+func ConfigLookup(cfg ConfigInterface) func(key string) string {
+	return func(key string) string {
+		value, err := cast.ToStringE(cfg.Get(key))
+		if err != nil {
+			return ""
+		}
+		return value
+	}
+}
+
+// lookupFunc returns the lookup function used by the Must* helpers.
+func (a *LoadAccumulator) lookupFunc() func(key string) string {
+	if a.lookup == nil {
+		return envLookup
+	}
+	return a.lookup
 }
 
 // Add appends err to the accumulator when it is non-nil.
@@ -19,7 +63,8 @@ func (a *LoadAccumulator) Add(err error) {
 // MustString returns the value for key via RequireString, while recording any
 // resulting error for later inspection.
 func (a *LoadAccumulator) MustString(key, context string) string {
-	value, err := RequireString(key, context)
+	a.declare(key)
+	value, err := requireString(a.lookupFunc(), key, context)
 	a.Add(err)
 	return value
 }
@@ -27,11 +72,110 @@ func (a *LoadAccumulator) MustString(key, context string) string {
 // MustWhen delegates to RequireWhen and records any error produced under the
 // supplied condition.
 func (a *LoadAccumulator) MustWhen(condition bool, key, context, value string) {
+	a.declare(key)
 	if err := RequireWhen(condition, key, context, value); err != nil {
 		a.Add(err)
 	}
 }
 
+// MustInt returns the value for key via RequireInt, while recording any
+// resulting error for later inspection.
+func (a *LoadAccumulator) MustInt(key, context string) int {
+	a.declare(key)
+	value, err := requireInt(a.lookupFunc(), key, context)
+	a.Add(err)
+	return value
+}
+
+// MustIntInRange returns the value for key via RequireIntInRange, while
+// recording any resulting error for later inspection.
+func (a *LoadAccumulator) MustIntInRange(key, context string, min, max int) int {
+	a.declare(key)
+	value, err := requireIntInRange(a.lookupFunc(), key, context, min, max)
+	a.Add(err)
+	return value
+}
+
+// MustPort returns the value for key via RequirePort, while recording any
+// resulting error for later inspection.
+func (a *LoadAccumulator) MustPort(key, context string) int {
+	a.declare(key)
+	value, err := requirePort(a.lookupFunc(), key, context)
+	a.Add(err)
+	return value
+}
+
+// MustBool returns the value for key via RequireBool, while recording any
+// resulting error for later inspection.
+func (a *LoadAccumulator) MustBool(key, context string) bool {
+	a.declare(key)
+	value, err := requireBool(a.lookupFunc(), key, context)
+	a.Add(err)
+	return value
+}
+
+// MustDuration returns the value for key via RequireDuration, while
+// recording any resulting error for later inspection.
+func (a *LoadAccumulator) MustDuration(key, context string) time.Duration {
+	a.declare(key)
+	value, err := requireDuration(a.lookupFunc(), key, context)
+	a.Add(err)
+	return value
+}
+
+// MustDurationInRange returns the value for key via RequireDurationInRange,
+// while recording any resulting error for later inspection.
+func (a *LoadAccumulator) MustDurationInRange(key, context string, min, max time.Duration) time.Duration {
+	a.declare(key)
+	value, err := requireDurationInRange(a.lookupFunc(), key, context, min, max)
+	a.Add(err)
+	return value
+}
+
+// MustURL returns the value for key via RequireURL, while recording any
+// resulting error for later inspection. Nil is returned on failure.
+func (a *LoadAccumulator) MustURL(key, context string) *url.URL {
+	a.declare(key)
+	value, err := requireURL(a.lookupFunc(), key, context)
+	a.Add(err)
+	return value
+}
+
+// MustEnum returns the value for key via RequireEnum, while recording any
+// resulting error for later inspection.
+func (a *LoadAccumulator) MustEnum(key, context string, allowed ...string) string {
+	a.declare(key)
+	value, err := requireEnum(a.lookupFunc(), key, context, allowed...)
+	a.Add(err)
+	return value
+}
+
+// MustSatisfy checks each rule against the environment, or the lookup
+// function of the accumulator, recording every violation for later
+// inspection.
+func (a *LoadAccumulator) MustSatisfy(rules ...Rule) {
+	for _, rule := range rules {
+		for _, key := range rule.keys {
+			a.declare(key)
+		}
+		a.Add(rule.Evaluate(a.lookupFunc()))
+	}
+}
+
+// Keys returns the environment keys declared through the Must* helpers, in
+// the order they were first requested.
+func (a *LoadAccumulator) Keys() []string {
+	return append([]string(nil), a.keys...)
+}
+
+// declare records key as read by this accumulator.
+func (a *LoadAccumulator) declare(key string) {
+	if slices.Contains(a.keys, key) {
+		return
+	}
+	a.keys = append(a.keys, key)
+}
+
 // Err returns a ValidationError wrapping all collected issues. Nil is returned
 // when no errors were recorded.
 func (a *LoadAccumulator) Err() error {
@@ -235,3 +235,29 @@ func TestLoadAccumulator_Integration(t *testing.T) {
 		t.Errorf("ValidationError has %d errors, want 2", len(validationErr.Errors()))
 	}
 }
+
+func TestNewLookupAccumulator(t *testing.T) {
+	t.Setenv("APP_PORT", "1")
+
+	cfg := NewConfig()
+	cfg.Set("APP_PORT", float64(8080))
+	cfg.Set("APP_DEBUG", true)
+	cfg.Set("APP_MODE", "fast")
+
+	acc := NewLookupAccumulator(ConfigLookup(cfg))
+
+	if port := acc.MustPort("APP_PORT", "required by server"); port != 8080 {
+		t.Errorf("MustPort() = %d, want 8080 from the config, not the environment", port)
+	}
+	if debug := acc.MustBool("APP_DEBUG", "required by logger"); !debug {
+		t.Error("MustBool() = false, want true")
+	}
+	acc.MustEnum("APP_MODE", "required by worker", "slow", "normal")
+	acc.MustString("APP_NAME", "required by server")
+	acc.MustSatisfy(RequireOneOf("SMTP_HOST", "MAIL_API_KEY"))
+
+	validationErr, ok := acc.Err().(ValidationError)
+	if !ok || len(validationErr.Errors()) != 3 {
+		t.Fatalf("LoadAccumulator.Err() = %v, want 3 errors", acc.Err())
+	}
+}
@@ -0,0 +1,239 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/dracory/base/cli"
+)
+
+// SourceUnset marks a declared key that no source supplied.
+const SourceUnset = "unset"
+
+// DefaultSecretPatterns are the key patterns (path.Match syntax, matched
+// case-insensitively) whose values are masked when no patterns are given.
+var DefaultSecretPatterns = []string{
+	"*PASSWORD*",
+	"*SECRET*",
+	"*TOKEN*",
+	"*PRIVATE*",
+	"*CREDENTIAL*",
+	"*_KEY",
+	"*_KEY_*",
+	"*_DSN",
+}
+
+// defaultMask replaces the value of secret keys.
+const defaultMask = "********"
+
+// DumpOptions controls how configuration values are rendered.
+type DumpOptions struct {
+	// SecretKeys lists keys that are always masked
+	SecretKeys []string
+
+	// SecretPatterns lists key patterns to mask; DefaultSecretPatterns is
+	// used when empty
+	SecretPatterns []string
+
+	// Mask replaces secret values; defaults to "********"
+	Mask string
+}
+
+// DumpEntry describes one effective configuration value.
+type DumpEntry struct {
+	Key    string `json:"key"`
+	Source string `json:"source"`
+	Value  string `json:"value"`
+
+	// Secret reports whether the value, or a nested member of it, is masked
+	Secret bool `json:"secret"`
+}
+
+// IsSecret reports whether key is listed in SecretKeys or matches one of the
+// secret patterns.
+func (o DumpOptions) IsSecret(key string) bool {
+	if slices.Contains(o.SecretKeys, key) {
+		return true
+	}
+
+	patterns := o.SecretPatterns
+	if len(patterns) == 0 {
+		patterns = DefaultSecretPatterns
+	}
+
+	upperKey := strings.ToUpper(key)
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToUpper(pattern), upperKey); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// entry builds a DumpEntry for key, masking non-empty secret values.
+func (o DumpOptions) entry(key, source, value string) DumpEntry {
+	secret := o.IsSecret(key)
+	if secret && value != "" {
+		value = o.mask()
+	}
+	return DumpEntry{Key: key, Source: source, Value: value, Secret: secret}
+}
+
+// mask returns the replacement for secret values.
+func (o DumpOptions) mask() string {
+	if o.Mask == "" {
+		return defaultMask
+	}
+	return o.Mask
+}
+
+// maskNested returns a copy of value with the members of nested maps masked
+// when their dotted path below key (e.g. "database.password") or their own
+// name is secret, and whether any member was masked.
+func (o DumpOptions) maskNested(key string, value any) (any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		masked := make(map[string]any, len(v))
+		found := false
+		for name, member := range v {
+			memberPath := key + "." + name
+			if (o.IsSecret(memberPath) || o.IsSecret(name)) && member != nil && member != "" {
+				masked[name] = o.mask()
+				found = true
+				continue
+			}
+			var memberFound bool
+			masked[name], memberFound = o.maskNested(memberPath, member)
+			found = found || memberFound
+		}
+		return masked, found
+	case map[string]string:
+		generic := make(map[string]any, len(v))
+		for name, member := range v {
+			generic[name] = member
+		}
+		return o.maskNested(key, generic)
+	case []any:
+		masked := make([]any, len(v))
+		found := false
+		for i, item := range v {
+			var itemFound bool
+			masked[i], itemFound = o.maskNested(key, item)
+			found = found || itemFound
+		}
+		return masked, found
+	default:
+		return value, false
+	}
+}
+
+// DumpConfig lists every key held by cfg together with the source recorded
+// in provenance (which may be nil), masking secret values, including the
+// secret members of nested maps and lists loaded from JSON files. Entries
+// are sorted by key.
+func DumpConfig(cfg ConfigInterface, provenance Provenance, options DumpOptions) []DumpEntry {
+	keys := cfg.Keys()
+	sort.Strings(keys)
+
+	entries := make([]DumpEntry, 0, len(keys))
+	for _, key := range keys {
+		value, nested := options.maskNested(key, cfg.Get(key))
+		entry := options.entry(key, provenance.SourceOf(key), dumpValue(value))
+		entry.Secret = entry.Secret || nested
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// DumpEnv lists the given keys with their current process environment
+// values, masking secret values. Keys that are not set are reported with
+// SourceUnset. Use LoadAccumulator.Keys to dump the keys an accumulator read.
+func DumpEnv(keys []string, options DumpOptions) []DumpEntry {
+	entries := make([]DumpEntry, 0, len(keys))
+	for _, key := range keys {
+		value, found := os.LookupEnv(key)
+		source := SourceEnv
+		if !found {
+			source = SourceUnset
+		}
+		entries = append(entries, options.entry(key, source, value))
+	}
+
+	return entries
+}
+
+// WriteDumpTable renders entries as an aligned KEY/SOURCE/VALUE table.
+func WriteDumpTable(w io.Writer, entries []DumpEntry) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tSOURCE\tVALUE")
+	for _, entry := range entries {
+		source := entry.Source
+		if source == "" {
+			source = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", entry.Key, source, entry.Value)
+	}
+	return tw.Flush()
+}
+
+// WriteDumpJSON renders entries as an indented JSON array.
+func WriteDumpJSON(w io.Writer, entries []DumpEntry) error {
+	if entries == nil {
+		entries = []DumpEntry{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(entries)
+}
+
+// NewDumpCommand returns a cli.CommandHandler that prints the entries
+// produced by dump to w, as a table by default or as JSON when the "--json"
+// argument is given.
+//
+// Example:
+//
+//	dispatcher.RegisterCommand("config:dump", "Print the effective configuration",
+//		config.NewDumpCommand(os.Stdout, func(app *App) ([]config.DumpEntry, error) {
+//			return config.DumpConfig(app.Config, app.Provenance, config.DumpOptions{}), nil
+//		}))
+func NewDumpCommand[T any](w io.Writer, dump func(registry T) ([]DumpEntry, error)) cli.CommandHandler[T] {
+	return func(registry T, args []string) error {
+		entries, err := dump(registry)
+		if err != nil {
+			return err
+		}
+
+		if slices.Contains(args, "--json") {
+			return WriteDumpJSON(w, entries)
+		}
+
+		return WriteDumpTable(w, entries)
+	}
+}
+
+// dumpValue converts a configuration value to its display form.
+func dumpValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]any, []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,187 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dracory/base/cli"
+)
+
+func TestDumpOptions_IsSecret(t *testing.T) {
+	tests := []struct {
+		key     string
+		options DumpOptions
+		want    bool
+	}{
+		{"DB_PASSWORD", DumpOptions{}, true},
+		{"db_password", DumpOptions{}, true},
+		{"STRIPE_SECRET_KEY", DumpOptions{}, true},
+		{"API_KEY", DumpOptions{}, true},
+		{"ENV_ENCRYPTION_KEY_PRIVATE", DumpOptions{}, true},
+		{"DATABASE_DSN", DumpOptions{}, true},
+		{"APP_HOST", DumpOptions{}, false},
+		{"KEYBOARD_LAYOUT", DumpOptions{}, false},
+		{"APP_HOST", DumpOptions{SecretKeys: []string{"APP_HOST"}}, true},
+		{"DB_PASSWORD", DumpOptions{SecretPatterns: []string{"*_HOST"}}, false},
+		{"APP_HOST", DumpOptions{SecretPatterns: []string{"*_HOST"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := tt.options.IsSecret(tt.key); got != tt.want {
+				t.Errorf("IsSecret(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDumpConfig(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Unset("id")
+	cfg.Set("APP_PORT", float64(8080))
+	cfg.Set("DB_PASSWORD", "hunter2")
+	cfg.Set("EMPTY_TOKEN", "")
+	cfg.Set("FEATURES", []any{"a", "b"})
+
+	provenance := Provenance{"APP_PORT": SourceDefault, "DB_PASSWORD": SourceVault}
+
+	entries := DumpConfig(cfg, provenance, DumpOptions{})
+
+	expected := []DumpEntry{
+		{Key: "APP_PORT", Source: SourceDefault, Value: "8080"},
+		{Key: "DB_PASSWORD", Source: SourceVault, Value: "********", Secret: true},
+		{Key: "EMPTY_TOKEN", Source: "", Value: "", Secret: true},
+		{Key: "FEATURES", Source: "", Value: `["a","b"]`},
+	}
+
+	if len(entries) != len(expected) {
+		t.Fatalf("DumpConfig() returned %d entries, want %d: %+v", len(entries), len(expected), entries)
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], expected[i])
+		}
+	}
+
+	masked := DumpConfig(cfg, nil, DumpOptions{Mask: "[redacted]"})
+	if masked[1].Value != "[redacted]" || masked[1].Source != "" {
+		t.Errorf("DumpConfig() with custom mask = %+v", masked[1])
+	}
+}
+
+func TestDumpConfig_NestedSecrets(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Unset("id")
+	if err := cfg.FromJSON([]byte(`{
+		"database": {"host": "localhost", "password": "hunter2", "replica": {"dsn": "x"}},
+		"mail": {"smtp": {"user": "me", "api_key": "k-123"}},
+		"webhooks": [{"url": "https://example.com", "token": "t-456"}]
+	}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := DumpConfig(cfg, nil, DumpOptions{SecretPatterns: append([]string{"*.REPLICA.DSN"}, DefaultSecretPatterns...)})
+
+	expected := []DumpEntry{
+		{Key: "database", Value: `{"host":"localhost","password":"********","replica":{"dsn":"********"}}`, Secret: true},
+		{Key: "mail", Value: `{"smtp":{"api_key":"********","user":"me"}}`, Secret: true},
+		{Key: "webhooks", Value: `[{"token":"********","url":"https://example.com"}]`, Secret: true},
+	}
+
+	if len(entries) != len(expected) {
+		t.Fatalf("DumpConfig() returned %d entries, want %d: %+v", len(entries), len(expected), entries)
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], expected[i])
+		}
+	}
+
+	if strings.Contains(entries[0].Value+entries[1].Value+entries[2].Value, "hunter2") {
+		t.Error("nested secrets leaked")
+	}
+	if database, _ := cfg.Get("database").(map[string]any); database["password"] != "hunter2" {
+		t.Error("DumpConfig must not modify the config")
+	}
+}
+
+func TestDumpEnv_AccumulatorKeys(t *testing.T) {
+	t.Setenv("DUMP_TEST_HOST", "localhost")
+	t.Setenv("DUMP_TEST_API_KEY", "abc123")
+
+	acc := &LoadAccumulator{}
+	acc.MustString("DUMP_TEST_HOST", "server")
+	acc.MustString("DUMP_TEST_API_KEY", "api")
+	acc.MustString("DUMP_TEST_HOST", "server again")
+	acc.MustPort("DUMP_TEST_MISSING_PORT", "server")
+
+	entries := DumpEnv(acc.Keys(), DumpOptions{})
+
+	expected := []DumpEntry{
+		{Key: "DUMP_TEST_HOST", Source: SourceEnv, Value: "localhost"},
+		{Key: "DUMP_TEST_API_KEY", Source: SourceEnv, Value: "********", Secret: true},
+		{Key: "DUMP_TEST_MISSING_PORT", Source: SourceUnset, Value: ""},
+	}
+
+	if len(entries) != len(expected) {
+		t.Fatalf("DumpEnv() returned %d entries, want %d: %+v", len(entries), len(expected), entries)
+	}
+	for i := range expected {
+		if entries[i] != expected[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], expected[i])
+		}
+	}
+}
+
+func TestNewDumpCommand(t *testing.T) {
+	entries := []DumpEntry{
+		{Key: "APP_HOST", Source: SourceEnv, Value: "localhost"},
+		{Key: "DB_PASSWORD", Source: SourceVault, Value: "********", Secret: true},
+	}
+
+	var out bytes.Buffer
+	dispatcher := cli.NewDispatcher[string]()
+	err := dispatcher.RegisterCommand("config:dump", "Print configuration", NewDumpCommand(&out, func(registry string) ([]DumpEntry, error) {
+		return entries, nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := dispatcher.ExecuteCommand("registry", []string{"config:dump"}); err != nil {
+		t.Fatalf("ExecuteCommand() unexpected error: %v", err)
+	}
+
+	table := out.String()
+	for _, want := range []string{"KEY", "SOURCE", "VALUE", "APP_HOST", "localhost", "DB_PASSWORD", "********"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("table output missing %q:\n%s", want, table)
+		}
+	}
+	if strings.Contains(table, "hunter2") {
+		t.Error("table output leaked a secret")
+	}
+
+	out.Reset()
+	if err := dispatcher.ExecuteCommand("registry", []string{"config:dump", "--json"}); err != nil {
+		t.Fatalf("ExecuteCommand() unexpected error: %v", err)
+	}
+
+	var decoded []DumpEntry
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("JSON output is invalid: %v\n%s", err, out.String())
+	}
+	if len(decoded) != 2 || decoded[1] != entries[1] {
+		t.Errorf("JSON output = %+v", decoded)
+	}
+
+	failing := NewDumpCommand(&out, func(registry string) ([]DumpEntry, error) {
+		return nil, errors.New("boom")
+	})
+	if err := failing("registry", nil); err == nil {
+		t.Error("NewDumpCommand() should propagate dump errors")
+	}
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"strings"
+)
+
+// sharedVaultName is the vault shared by every environment.
+const sharedVaultName = ".env.vault"
+
+// VaultChain describes where, and in which order, EnvEnc vaults are looked
+// up for an environment.
+//
+// For each environment the chain reads, lowest precedence first: the shared
+// ".env.vault", then ".env.<parent>.vault" for every inherited profile, and
+// finally ".env.<app_environment>.vault". Later vaults override keys set by
+// earlier ones. The environment's own vault must exist, so a mistyped
+// environment name fails instead of silently loading only the shared vault;
+// missing shared and inherited vaults are skipped.
+type VaultChain struct {
+	// FileSystems are searched in order for each vault file, the first one
+	// holding the file wins, e.g. os.DirFS(".") before an embed.FS so an
+	// on-disk hotfix overrides the embedded vault
+	FileSystems []fs.FS
+
+	// Inherits maps an environment to the profile it inherits from, e.g.
+	// {"staging": "production"}
+	Inherits map[string]string
+
+	// SkipShared disables the shared ".env.vault" base layer
+	SkipShared bool
+
+	// AllowMissingEnvironment accepts a missing ".env.<app_environment>.vault"
+	// as long as another vault of the chain exists, e.g. for local
+	// environments configured by the shared vault only
+	AllowMissingEnvironment bool
+}
+
+// Files returns the vault file names consulted for appEnvironment, lowest
+// precedence first.
+func (c VaultChain) Files(appEnvironment string) ([]string, error) {
+	profiles := []string{}
+	visited := map[string]bool{}
+
+	for profile := strings.ToLower(appEnvironment); profile != ""; profile = strings.ToLower(c.Inherits[profile]) {
+		if visited[profile] {
+			return nil, &EnvEncError{
+				Operation: "inherit_cycle",
+				Message:   "Profile inheritance cycle at '" + profile + "'",
+			}
+		}
+		visited[profile] = true
+		profiles = append([]string{".env." + profile + ".vault"}, profiles...)
+	}
+
+	if !c.SkipShared {
+		profiles = append([]string{sharedVaultName}, profiles...)
+	}
+
+	return profiles, nil
+}
+
+// Load decrypts and merges the vaults of the chain for appEnvironment.
+//
+// Returns:
+//   - map[string]string: the merged variables
+//   - []string: the vault files that were read, lowest precedence first
+//   - error: If the environment's vault (or, with AllowMissingEnvironment,
+//     every vault) is missing, or any vault fails to decrypt
+func (c VaultChain) Load(appEnvironment, publicKey, privateKey string) (map[string]string, []string, error) {
+	if err := validateInputs(appEnvironment, publicKey, privateKey); err != nil {
+		return nil, nil, err
+	}
+
+	files, err := c.Files(appEnvironment)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merged := map[string]string{}
+	read := []string{}
+
+	for i, name := range files {
+		content, found, err := c.readFirst(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !found && i == len(files)-1 && !c.AllowMissingEnvironment {
+			return nil, nil, &EnvEncError{
+				Operation: "vault_not_found",
+				Message:   "Vault '" + name + "' for environment '" + appEnvironment + "' not found",
+			}
+		}
+		if !found {
+			continue
+		}
+
+		keys, err := decryptVaultContent(name, content, publicKey, privateKey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for k, v := range keys {
+			merged[k] = v
+		}
+		read = append(read, name)
+	}
+
+	if len(read) == 0 {
+		return nil, nil, &EnvEncError{
+			Operation: "vault_not_found",
+			Message:   "No vault found, tried: '" + strings.Join(files, "', '") + "'",
+		}
+	}
+
+	return merged, read, nil
+}
+
+// readFirst returns the content of name from the first file system that
+// holds it.
+func (c VaultChain) readFirst(name string) (string, bool, error) {
+	for _, fsys := range c.FileSystems {
+		if fsys == nil {
+			continue
+		}
+
+		content, err := fs.ReadFile(fsys, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", false, &EnvEncError{
+				Operation: "read_vault",
+				Message:   "Failed to read vault file '" + name + "': " + err.Error(),
+			}
+		}
+
+		return string(content), true, nil
+	}
+
+	return "", false, nil
+}
+
+// InitializeEnvEncVariablesFromChain hydrates the target selected in options
+// from the merged vaults of chain for appEnvironment.
+//
+// Example:
+//
+//	//go:embed .env.*vault
+//	var vaults embed.FS
+//
+//	chain := config.VaultChain{
+//		FileSystems: []fs.FS{os.DirFS("."), vaults},
+//		Inherits:    map[string]string{"staging": "production"},
+//	}
+//	report, err := config.InitializeEnvEncVariablesFromChain(chain, "staging", publicKey, privateKey, config.HydrateOptions{})
+//
+// Returns:
+//   - HydrateReport: the keys loaded, skipped and conflicting, and the vaults read
+//   - error: If any step fails, or a conflict occurs under HydrateFailOnConflict
+func InitializeEnvEncVariablesFromChain(chain VaultChain, appEnvironment, publicKey, privateKey string, options HydrateOptions) (HydrateReport, error) {
+	keys, vaults, err := chain.Load(appEnvironment, publicKey, privateKey)
+	if err != nil {
+		return HydrateReport{}, err
+	}
+
+	report, err := hydrate(keys, options)
+	report.Vaults = vaults
+	return report, err
+}
+
+// InitializeEnvEncVariablesFromFS initializes environment variables from the
+// ".env.<app_environment>.vault" file held by fsys, such as an embed.FS or
+// os.DirFS. Existing variables are overwritten, like
+// InitializeEnvEncVariablesFromFile.
+func InitializeEnvEncVariablesFromFS(fsys fs.FS, appEnvironment, publicKey, privateKey string) error {
+	_, err := InitializeEnvEncVariablesFromFSWithOptions(fsys, appEnvironment, publicKey, privateKey, HydrateOptions{})
+	return err
+}
+
+// InitializeEnvEncVariablesFromFSWithOptions is the options-based variant of
+// InitializeEnvEncVariablesFromFS.
+func InitializeEnvEncVariablesFromFSWithOptions(fsys fs.FS, appEnvironment, publicKey, privateKey string, options HydrateOptions) (HydrateReport, error) {
+	if fsys == nil {
+		return HydrateReport{}, &MissingEnvError{
+			Key:     "fsys",
+			Context: "required to load vault files",
+		}
+	}
+
+	chain := VaultChain{FileSystems: []fs.FS{fsys}, SkipShared: true}
+	return InitializeEnvEncVariablesFromChain(chain, appEnvironment, publicKey, privateKey, options)
+}
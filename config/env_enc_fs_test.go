@@ -0,0 +1,145 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// vaultContent creates a vault holding values and returns its encrypted
+// content together with the public key that opens it.
+func vaultContent(t *testing.T, values map[string]string) (string, string) {
+	t.Helper()
+	path, publicKey := createTestVault(t, t.TempDir(), "content.vault", values)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data), publicKey
+}
+
+func TestVaultChain_Files(t *testing.T) {
+	chain := VaultChain{Inherits: map[string]string{"staging": "production", "production": ""}}
+
+	files, err := chain.Files("Staging")
+	if err != nil {
+		t.Fatalf("Files() unexpected error: %v", err)
+	}
+	want := []string{".env.vault", ".env.production.vault", ".env.staging.vault"}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("Files() = %v, want %v", files, want)
+	}
+
+	chain.SkipShared = true
+	files, _ = chain.Files("development")
+	if !reflect.DeepEqual(files, []string{".env.development.vault"}) {
+		t.Errorf("Files() with SkipShared = %v", files)
+	}
+
+	cyclic := VaultChain{Inherits: map[string]string{"a": "b", "b": "a"}}
+	var envEncErr *EnvEncError
+	if _, err := cyclic.Files("a"); !errors.As(err, &envEncErr) || envEncErr.Operation != "inherit_cycle" {
+		t.Errorf("Files() with cycle error = %v, want inherit_cycle", err)
+	}
+}
+
+func TestInitializeEnvEncVariablesFromChain(t *testing.T) {
+	shared, publicKey := vaultContent(t, map[string]string{"CHAIN_SHARED": "shared", "CHAIN_LEVEL": "shared"})
+	production, _ := vaultContent(t, map[string]string{"CHAIN_LEVEL": "production", "CHAIN_DB": "prod-db"})
+	staging, _ := vaultContent(t, map[string]string{"CHAIN_LEVEL": "staging"})
+	hotfix, _ := vaultContent(t, map[string]string{"CHAIN_LEVEL": "hotfix", "CHAIN_DB": "hotfix-db"})
+
+	embedded := fstest.MapFS{
+		".env.vault":            {Data: []byte(shared)},
+		".env.production.vault": {Data: []byte(production)},
+		".env.staging.vault":    {Data: []byte(staging)},
+	}
+	disk := fstest.MapFS{
+		".env.production.vault": {Data: []byte(hotfix)},
+	}
+
+	chain := VaultChain{
+		FileSystems: []fs.FS{disk, embedded},
+		Inherits:    map[string]string{"staging": "production"},
+	}
+
+	t.Run("staging inherits production", func(t *testing.T) {
+		target := map[string]string{}
+		report, err := InitializeEnvEncVariablesFromChain(chain, "staging", publicKey, testVaultPrivateKey, HydrateOptions{Target: target})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		want := map[string]string{"CHAIN_SHARED": "shared", "CHAIN_LEVEL": "staging", "CHAIN_DB": "hotfix-db"}
+		if !reflect.DeepEqual(target, want) {
+			t.Errorf("target = %v, want %v", target, want)
+		}
+		if !reflect.DeepEqual(report.Vaults, []string{".env.vault", ".env.production.vault", ".env.staging.vault"}) {
+			t.Errorf("report.Vaults = %v", report.Vaults)
+		}
+	})
+
+	t.Run("missing environment vault", func(t *testing.T) {
+		var envEncErr *EnvEncError
+		_, err := InitializeEnvEncVariablesFromChain(chain, "prodution", publicKey, testVaultPrivateKey, HydrateOptions{Target: map[string]string{}})
+		if !errors.As(err, &envEncErr) || envEncErr.Operation != "vault_not_found" {
+			t.Errorf("error = %v, want vault_not_found", err)
+		}
+	})
+
+	t.Run("falls back to shared vault when allowed", func(t *testing.T) {
+		sharedOnly := chain
+		sharedOnly.AllowMissingEnvironment = true
+
+		target := map[string]string{}
+		report, err := InitializeEnvEncVariablesFromChain(sharedOnly, "development", publicKey, testVaultPrivateKey, HydrateOptions{Target: target})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if target["CHAIN_LEVEL"] != "shared" || !reflect.DeepEqual(report.Vaults, []string{".env.vault"}) {
+			t.Errorf("target = %v, vaults = %v", target, report.Vaults)
+		}
+	})
+
+	t.Run("no vault found", func(t *testing.T) {
+		empty := VaultChain{FileSystems: []fs.FS{fstest.MapFS{}}, AllowMissingEnvironment: true}
+		var envEncErr *EnvEncError
+		_, err := InitializeEnvEncVariablesFromChain(empty, "development", publicKey, testVaultPrivateKey, HydrateOptions{Target: map[string]string{}})
+		if !errors.As(err, &envEncErr) || envEncErr.Operation != "vault_not_found" {
+			t.Errorf("error = %v, want vault_not_found", err)
+		}
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		_, err := InitializeEnvEncVariablesFromChain(chain, "staging", publicKey, "wrong-private-key-0123456789abcdefghijklmnop", HydrateOptions{Target: map[string]string{}})
+		if err == nil {
+			t.Error("expected decryption error")
+		}
+	})
+}
+
+func TestInitializeEnvEncVariablesFromFS(t *testing.T) {
+	content, publicKey := vaultContent(t, map[string]string{"FS_TEST_KEY": "from-fs"})
+	fsys := fstest.MapFS{".env.fstest.vault": {Data: []byte(content)}}
+
+	t.Setenv("FS_TEST_KEY", "")
+
+	if err := InitializeEnvEncVariablesFromFS(fsys, "FsTest", publicKey, testVaultPrivateKey); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := os.Getenv("FS_TEST_KEY"); got != "from-fs" {
+		t.Errorf("FS_TEST_KEY = %q, want from-fs", got)
+	}
+
+	if err := InitializeEnvEncVariablesFromFS(fsys, "other", publicKey, testVaultPrivateKey); err == nil {
+		t.Error("expected error for missing vault")
+	}
+
+	var missingErr *MissingEnvError
+	if err := InitializeEnvEncVariablesFromFS(nil, "fstest", publicKey, testVaultPrivateKey); !errors.As(err, &missingErr) {
+		t.Errorf("error = %v, want *MissingEnvError", err)
+	}
+}
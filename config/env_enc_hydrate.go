@@ -0,0 +1,237 @@
+package config
+
+import (
+	"os"
+	"strings"
+
+	"github.com/dracory/envenc"
+)
+
+// HydrateMode decides what happens when a vault key is already set in the
+// hydration target.
+type HydrateMode int
+
+const (
+	// HydrateVaultWins overwrites existing values with the vault values.
+	// This matches InitializeEnvEncVariablesFromFile.
+	HydrateVaultWins HydrateMode = iota
+
+	// HydrateEnvironmentWins keeps existing values and skips the vault values.
+	HydrateEnvironmentWins
+
+	// HydrateFailOnConflict returns an error, without writing anything, when
+	// an existing value differs from the vault value.
+	HydrateFailOnConflict
+)
+
+// HydrateOptions configures the options-based hydration functions.
+type HydrateOptions struct {
+	// Mode decides how existing values are treated (default HydrateVaultWins)
+	Mode HydrateMode
+
+	// Target, when non-nil, receives the values instead of the process
+	// environment. Existing entries are checked for conflicts.
+	Target map[string]string
+
+	// Config, when non-nil and Target is nil, receives the values instead
+	// of the process environment. Existing keys are checked for conflicts.
+	Config ConfigInterface
+}
+
+// HydrateReport describes the outcome of an options-based hydration. All
+// key lists are sorted.
+type HydrateReport struct {
+	// Loaded holds the keys written to the target
+	Loaded []string
+
+	// Skipped holds the keys left untouched because the target already
+	// had a value (HydrateEnvironmentWins) or the same value
+	Skipped []string
+
+	// Conflicts holds the keys whose existing value differed from the vault
+	Conflicts []string
+
+	// Vaults holds the vault files that were read, lowest precedence first
+	Vaults []string
+}
+
+// InitializeEnvEncVariablesFromFileWithOptions is the options-based variant
+// of InitializeEnvEncVariablesFromFile. It reads ".env.<app_environment>.vault"
+// from the local filesystem and hydrates the target selected in options
+// according to options.Mode, reporting which keys were loaded, skipped and
+// conflicting.
+//
+// Returns:
+//   - HydrateReport: the keys loaded, skipped and conflicting
+//   - error: If any step fails, or a conflict occurs under HydrateFailOnConflict
+func InitializeEnvEncVariablesFromFileWithOptions(appEnvironment, publicKey, privateKey string, options HydrateOptions) (HydrateReport, error) {
+	if err := validateInputs(appEnvironment, publicKey, privateKey); err != nil {
+		return HydrateReport{}, err
+	}
+
+	vaultFilePath := ".env." + strings.ToLower(appEnvironment) + ".vault"
+
+	keys, err := NewVault(vaultFilePath, publicKey, privateKey).List()
+	if err != nil {
+		return HydrateReport{}, err
+	}
+
+	report, err := hydrate(keys, options)
+	report.Vaults = []string{vaultFilePath}
+	return report, err
+}
+
+// InitializeEnvEncVariablesFromResourcesWithOptions is the options-based
+// variant of InitializeEnvEncVariablesFromResources. It loads
+// ".env.<app_environment>.vault" via resourceLoader and hydrates the target
+// selected in options according to options.Mode.
+//
+// Returns:
+//   - HydrateReport: the keys loaded, skipped and conflicting
+//   - error: If any step fails, or a conflict occurs under HydrateFailOnConflict
+func InitializeEnvEncVariablesFromResourcesWithOptions(appEnvironment, publicKey, privateKey string, resourceLoader func(string) (string, error), options HydrateOptions) (HydrateReport, error) {
+	if err := validateInputs(appEnvironment, publicKey, privateKey); err != nil {
+		return HydrateReport{}, err
+	}
+
+	if resourceLoader == nil {
+		return HydrateReport{}, &MissingEnvError{
+			Key:     "resourceLoader",
+			Context: "required to load embedded resources",
+		}
+	}
+
+	resourceName := ".env." + strings.ToLower(appEnvironment) + ".vault"
+
+	vaultContent, err := resourceLoader(resourceName)
+	if err != nil {
+		return HydrateReport{}, &EnvEncError{
+			Operation: "resource_not_found",
+			Message:   "Embedded resource not found: '" + resourceName + "' (" + err.Error() + ")",
+		}
+	}
+
+	keys, err := decryptVaultContent(resourceName, vaultContent, publicKey, privateKey)
+	if err != nil {
+		return HydrateReport{}, err
+	}
+
+	report, err := hydrate(keys, options)
+	report.Vaults = []string{resourceName}
+	return report, err
+}
+
+// decryptVaultContent derives the vault password and decrypts the vault
+// content loaded from the resource called name.
+func decryptVaultContent(name, vaultContent, publicKey, privateKey string) (map[string]string, error) {
+	if vaultContent == "" {
+		return nil, &EnvEncError{
+			Operation: "resource_empty",
+			Message:   "Embedded resource is empty: '" + name + "'",
+		}
+	}
+
+	derivedKey, err := envenc.DeriveKey(publicKey, privateKey)
+	if err != nil {
+		return nil, &EnvEncError{
+			Operation: "derive_key",
+			Message:   "Failed to derive encryption key: " + err.Error(),
+		}
+	}
+
+	keys, err := vaultKeyListFromString(vaultContent, derivedKey)
+	if err != nil {
+		return nil, &EnvEncError{
+			Operation: "read_vault",
+			Message:   "Failed to read vault resource '" + name + "': " + err.Error(),
+		}
+	}
+
+	return keys, nil
+}
+
+// hydrate writes keys into the target selected by options, honouring the
+// conflict mode.
+func hydrate(keys map[string]string, options HydrateOptions) (HydrateReport, error) {
+	sink := hydrateTarget(options)
+	report := HydrateReport{}
+
+	toWrite := []string{}
+	for _, key := range sortedKeys(keys) {
+		existing, found := sink.lookup(key)
+		if !found || strings.TrimSpace(existing) == "" {
+			toWrite = append(toWrite, key)
+			continue
+		}
+
+		if existing == keys[key] {
+			report.Skipped = append(report.Skipped, key)
+			continue
+		}
+
+		report.Conflicts = append(report.Conflicts, key)
+		if options.Mode == HydrateVaultWins {
+			toWrite = append(toWrite, key)
+		} else {
+			report.Skipped = append(report.Skipped, key)
+		}
+	}
+
+	if options.Mode == HydrateFailOnConflict && len(report.Conflicts) > 0 {
+		return HydrateReport{Conflicts: report.Conflicts}, &EnvEncError{
+			Operation: "hydrate_conflict",
+			Message:   "Vault keys conflict with existing values: " + strings.Join(report.Conflicts, ", "),
+		}
+	}
+
+	for _, key := range toWrite {
+		if err := sink.set(key, keys[key]); err != nil {
+			return report, &EnvEncError{
+				Operation: "hydrate",
+				Message:   "Failed to set '" + key + "': " + err.Error(),
+			}
+		}
+		report.Loaded = append(report.Loaded, key)
+	}
+
+	return report, nil
+}
+
+// hydrateSink reads and writes the values of a hydration target.
+type hydrateSink struct {
+	lookup func(key string) (string, bool)
+	set    func(key, value string) error
+}
+
+// hydrateTarget returns the sink selected by options: the Target map, the
+// Config object or, by default, the process environment.
+func hydrateTarget(options HydrateOptions) hydrateSink {
+	if options.Target != nil {
+		return hydrateSink{
+			lookup: func(key string) (string, bool) {
+				value, found := options.Target[key]
+				return value, found
+			},
+			set: func(key, value string) error {
+				options.Target[key] = value
+				return nil
+			},
+		}
+	}
+
+	if options.Config != nil {
+		return hydrateSink{
+			lookup: func(key string) (string, bool) {
+				if !options.Config.Has(key) {
+					return "", false
+				}
+				return dumpValue(options.Config.Get(key)), true
+			},
+			set: func(key, value string) error {
+				return options.Config.Set(key, value)
+			},
+		}
+	}
+
+	return hydrateSink{lookup: os.LookupEnv, set: os.Setenv}
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// setupHydrateVault creates ".env.hydratetest.vault" in a temporary working
+// directory and returns the public key that opens it.
+func setupHydrateVault(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	_, publicKey := createTestVault(t, dir, ".env.hydratetest.vault", map[string]string{
+		"HYDRATE_NEW":      "vault-new",
+		"HYDRATE_SAME":     "same",
+		"HYDRATE_CONFLICT": "vault-value",
+		"HYDRATE_BLANK":    "vault-blank",
+	})
+
+	return publicKey
+}
+
+func TestHydrateWithOptions_Modes(t *testing.T) {
+	publicKey := setupHydrateVault(t)
+
+	tests := []struct {
+		name         string
+		mode         HydrateMode
+		wantErr      bool
+		wantConflict string
+		wantReport   HydrateReport
+	}{
+		{
+			name:         "vault wins",
+			mode:         HydrateVaultWins,
+			wantConflict: "vault-value",
+			wantReport: HydrateReport{
+				Loaded:    []string{"HYDRATE_BLANK", "HYDRATE_CONFLICT", "HYDRATE_NEW"},
+				Skipped:   []string{"HYDRATE_SAME"},
+				Conflicts: []string{"HYDRATE_CONFLICT"},
+				Vaults:    []string{".env.hydratetest.vault"},
+			},
+		},
+		{
+			name:         "environment wins",
+			mode:         HydrateEnvironmentWins,
+			wantConflict: "orchestrator",
+			wantReport: HydrateReport{
+				Loaded:    []string{"HYDRATE_BLANK", "HYDRATE_NEW"},
+				Skipped:   []string{"HYDRATE_CONFLICT", "HYDRATE_SAME"},
+				Conflicts: []string{"HYDRATE_CONFLICT"},
+				Vaults:    []string{".env.hydratetest.vault"},
+			},
+		},
+		{
+			name:         "fail on conflict",
+			mode:         HydrateFailOnConflict,
+			wantErr:      true,
+			wantConflict: "orchestrator",
+			wantReport: HydrateReport{
+				Conflicts: []string{"HYDRATE_CONFLICT"},
+				Vaults:    []string{".env.hydratetest.vault"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := map[string]string{
+				"HYDRATE_SAME":     "same",
+				"HYDRATE_CONFLICT": "orchestrator",
+				"HYDRATE_BLANK":    "",
+			}
+
+			report, err := InitializeEnvEncVariablesFromFileWithOptions("HydrateTest", publicKey, testVaultPrivateKey, HydrateOptions{
+				Mode:   tt.mode,
+				Target: target,
+			})
+
+			if tt.wantErr {
+				var envEncErr *EnvEncError
+				if !errors.As(err, &envEncErr) || envEncErr.Operation != "hydrate_conflict" {
+					t.Fatalf("error = %v, want hydrate_conflict EnvEncError", err)
+				}
+				if target["HYDRATE_NEW"] != "" {
+					t.Error("nothing should be written when a conflict fails hydration")
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			} else if target["HYDRATE_NEW"] != "vault-new" || target["HYDRATE_BLANK"] != "vault-blank" {
+				t.Errorf("target = %v", target)
+			}
+
+			if target["HYDRATE_CONFLICT"] != tt.wantConflict {
+				t.Errorf("HYDRATE_CONFLICT = %q, want %q", target["HYDRATE_CONFLICT"], tt.wantConflict)
+			}
+
+			if !reflect.DeepEqual(report, tt.wantReport) {
+				t.Errorf("report = %+v, want %+v", report, tt.wantReport)
+			}
+		})
+	}
+}
+
+func TestHydrateWithOptions_ProcessEnvironment(t *testing.T) {
+	publicKey := setupHydrateVault(t)
+
+	t.Setenv("HYDRATE_CONFLICT", "orchestrator")
+	t.Setenv("HYDRATE_NEW", "")
+	t.Setenv("HYDRATE_SAME", "")
+	t.Setenv("HYDRATE_BLANK", "")
+
+	_, err := InitializeEnvEncVariablesFromFileWithOptions("hydratetest", publicKey, testVaultPrivateKey, HydrateOptions{
+		Mode: HydrateEnvironmentWins,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := os.Getenv("HYDRATE_CONFLICT"); got != "orchestrator" {
+		t.Errorf("HYDRATE_CONFLICT = %q, want orchestrator value kept", got)
+	}
+	if got := os.Getenv("HYDRATE_NEW"); got != "vault-new" {
+		t.Errorf("HYDRATE_NEW = %q, want vault-new", got)
+	}
+}
+
+func TestHydrateWithOptions_Config(t *testing.T) {
+	publicKey := setupHydrateVault(t)
+
+	content, err := os.ReadFile(".env.hydratetest.vault")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := NewConfig()
+	cfg.Set("HYDRATE_CONFLICT", "config-value")
+
+	loader := func(name string) (string, error) {
+		if name != ".env.hydratetest.vault" {
+			return "", os.ErrNotExist
+		}
+		return string(content), nil
+	}
+
+	report, err := InitializeEnvEncVariablesFromResourcesWithOptions("hydratetest", publicKey, testVaultPrivateKey, loader, HydrateOptions{
+		Mode:   HydrateVaultWins,
+		Config: cfg,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Get("HYDRATE_CONFLICT") != "vault-value" || cfg.Get("HYDRATE_NEW") != "vault-new" {
+		t.Errorf("config not hydrated: %v / %v", cfg.Get("HYDRATE_CONFLICT"), cfg.Get("HYDRATE_NEW"))
+	}
+	if !reflect.DeepEqual(report.Conflicts, []string{"HYDRATE_CONFLICT"}) || len(report.Loaded) != 4 {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestHydrateWithOptions_Errors(t *testing.T) {
+	publicKey := setupHydrateVault(t)
+
+	if _, err := InitializeEnvEncVariablesFromFileWithOptions("", publicKey, testVaultPrivateKey, HydrateOptions{}); err == nil {
+		t.Error("expected error for missing app environment")
+	}
+
+	var envEncErr *EnvEncError
+	_, err := InitializeEnvEncVariablesFromFileWithOptions("missing", publicKey, testVaultPrivateKey, HydrateOptions{})
+	if !errors.As(err, &envEncErr) || envEncErr.Operation != "vault_not_found" {
+		t.Errorf("error = %v, want vault_not_found", err)
+	}
+
+	if _, err := InitializeEnvEncVariablesFromResourcesWithOptions("hydratetest", publicKey, testVaultPrivateKey, nil, HydrateOptions{}); err == nil {
+		t.Error("expected error for nil resource loader")
+	}
+
+	empty := func(string) (string, error) { return "", nil }
+	_, err = InitializeEnvEncVariablesFromResourcesWithOptions("hydratetest", publicKey, testVaultPrivateKey, empty, HydrateOptions{})
+	if !errors.As(err, &envEncErr) || envEncErr.Operation != "resource_empty" {
+		t.Errorf("error = %v, want resource_empty", err)
+	}
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Provenance records, for each configuration key, the name of the source
+// whose value won.
+type Provenance map[string]string
+
+// SourceOf returns the name of the source that supplied key, or an empty
+// string when the key was not loaded from any source.
+func (p Provenance) SourceOf(key string) string {
+	return p[key]
+}
+
+// Keys returns the recorded keys in sorted order.
+func (p Provenance) Keys() []string {
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// LoadLayered merges the supplied sources into a new ConfigInterface. Sources
+// are applied in order, so a later source overrides keys set by an earlier
+// one. The recommended precedence, lowest first, is:
+//
+//	defaults, JSON config file, .env files, EnvEnc vault, OS environment, args
+//
+// Example:
+//
+//	cfg, provenance, err := config.LoadLayered(
+//		config.NewDefaultsSource(map[string]any{"APP_PORT": "8080"}),
+//		config.OptionalSource(config.NewJSONFileSource("config.json")),
+//		config.OptionalSource(config.NewDotEnvSource(".env")),
+//		config.NewVaultFileSource(".env.production.vault", publicKey, privateKey),
+//		config.NewEnvSource("APP_"),
+//		config.NewArgsSource(os.Args[1:]),
+//	)
+//
+// Returns:
+//   - ConfigInterface: the merged configuration
+//   - Provenance: the name of the winning source for each key
+//   - error: the first source error, annotated with the source name
+func LoadLayered(sources ...SourceInterface) (ConfigInterface, Provenance, error) {
+	cfg := NewConfig()
+	provenance := Provenance{}
+
+	// Drop the generated object id, so every key has a source
+	cfg.Unset("id")
+
+	for _, src := range sources {
+		if src == nil {
+			continue
+		}
+
+		values, err := src.Values()
+		if err != nil {
+			return nil, nil, fmt.Errorf("config: load source %q: %w", src.Name(), err)
+		}
+
+		for key, value := range values {
+			if err := cfg.Set(key, value); err != nil {
+				return nil, nil, fmt.Errorf("config: set %q from source %q: %w", key, src.Name(), err)
+			}
+			provenance[key] = src.Name()
+		}
+	}
+
+	return cfg, provenance, nil
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dracory/envenc"
+)
+
+const testVaultPrivateKey = "test-private-key-0123456789abcdefghijklmnop"
+
+// createTestVault writes an EnvEnc vault holding values into dir and returns
+// its path together with the public key needed to open it with
+// testVaultPrivateKey.
+func createTestVault(t *testing.T, dir, name string, values map[string]string) (string, string) {
+	t.Helper()
+
+	publicKey, err := envenc.Obfuscate("test-public-key-0123456789abcdefghijklmnop")
+	if err != nil {
+		t.Fatalf("Obfuscate() error: %v", err)
+	}
+
+	derivedKey, err := envenc.DeriveKey(publicKey, testVaultPrivateKey)
+	if err != nil {
+		t.Fatalf("DeriveKey() error: %v", err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := envenc.Init(path, derivedKey); err != nil {
+		t.Fatalf("envenc.Init() error: %v", err)
+	}
+
+	for k, v := range values {
+		if err := envenc.KeySet(path, derivedKey, k, v); err != nil {
+			t.Fatalf("envenc.KeySet() error: %v", err)
+		}
+	}
+
+	return path, publicKey
+}
+
+func TestLoadLayered_Precedence(t *testing.T) {
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(jsonPath, []byte(`{"LAYER_A":"file","LAYER_B":"file","LAYER_C":"file","LAYER_PORT":8080}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	dotEnvPath := filepath.Join(dir, ".env")
+	if err := os.WriteFile(dotEnvPath, []byte("LAYER_B=dotenv\nLAYER_C=dotenv\nLAYER_D=dotenv\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	vaultPath, publicKey := createTestVault(t, dir, ".env.test.vault", map[string]string{
+		"LAYER_C": "vault",
+		"LAYER_D": "vault",
+		"LAYER_E": "vault",
+	})
+
+	t.Setenv("LAYER_D", "env")
+	t.Setenv("LAYER_E", "env")
+
+	cfg, provenance, err := LoadLayered(
+		NewDefaultsSource(map[string]any{"LAYER_A": "default", "LAYER_DEFAULT": "default"}),
+		NewJSONFileSource(jsonPath),
+		NewDotEnvSource(dotEnvPath),
+		NewVaultFileSource(vaultPath, publicKey, testVaultPrivateKey),
+		NewEnvSource("LAYER_"),
+		NewArgsSource([]string{"--LAYER_E=args", "positional", "--no-equals"}),
+	)
+	if err != nil {
+		t.Fatalf("LoadLayered() unexpected error: %v", err)
+	}
+
+	expected := map[string][2]any{
+		"LAYER_DEFAULT": {"default", SourceDefault},
+		"LAYER_A":       {"file", SourceFile},
+		"LAYER_PORT":    {float64(8080), SourceFile},
+		"LAYER_B":       {"dotenv", SourceDotEnv},
+		"LAYER_C":       {"vault", SourceVault},
+		"LAYER_D":       {"env", SourceEnv},
+		"LAYER_E":       {"args", SourceArgs},
+	}
+
+	for key, want := range expected {
+		if got := cfg.Get(key); got != want[0] {
+			t.Errorf("Get(%q) = %v, want %v", key, got, want[0])
+		}
+		if got := provenance.SourceOf(key); got != want[1] {
+			t.Errorf("SourceOf(%q) = %q, want %q", key, got, want[1])
+		}
+	}
+
+	if len(provenance.Keys()) != len(expected) {
+		t.Errorf("Provenance has %d keys, want %d: %v", len(provenance.Keys()), len(expected), provenance.Keys())
+	}
+
+	if cfg.Count() != len(expected) || cfg.Has("id") {
+		t.Errorf("config keys = %v, want only the keys of the sources", cfg.Keys())
+	}
+
+	if cfg.Has("no-equals") || cfg.Has("positional") {
+		t.Error("NewArgsSource should ignore arguments without --KEY=value form")
+	}
+}
+
+func TestLoadLayered_SourceErrors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	_, _, err := LoadLayered(NewJSONFileSource(missing))
+	if err == nil {
+		t.Fatal("LoadLayered() expected error for missing file")
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("LoadLayered() error should wrap os.ErrNotExist, got %v", err)
+	}
+	if !strings.Contains(err.Error(), SourceFile) {
+		t.Errorf("LoadLayered() error should name the source, got %v", err)
+	}
+
+	cfg, provenance, err := LoadLayered(
+		OptionalSource(NewJSONFileSource(missing)),
+		OptionalSource(NewDotEnvSource(missing)),
+		OptionalSource(NewVaultFileSource(missing, "public", "private")),
+		NewDefaultsSource(map[string]any{"KEY": "value"}),
+	)
+	if err != nil {
+		t.Fatalf("LoadLayered() with optional sources unexpected error: %v", err)
+	}
+	if cfg.Get("KEY") != "value" || provenance.SourceOf("KEY") != SourceDefault {
+		t.Errorf("LoadLayered() did not apply defaults after optional sources")
+	}
+
+	badJSON := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(badJSON, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := LoadLayered(OptionalSource(NewJSONFileSource(badJSON))); err == nil {
+		t.Error("OptionalSource should not hide parse errors")
+	}
+}
+
+func TestNewVaultFileSource_WrongKey(t *testing.T) {
+	vaultPath, publicKey := createTestVault(t, t.TempDir(), ".env.test.vault", map[string]string{"KEY": "value"})
+
+	_, err := NewVaultFileSource(vaultPath, publicKey, "wrong-private-key-0123456789abcdefghijklmnop").Values()
+
+	var envEncErr *EnvEncError
+	if !errors.As(err, &envEncErr) {
+		t.Fatalf("Values() error = %v, want *EnvEncError", err)
+	}
+}
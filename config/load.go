@@ -0,0 +1,234 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dracory/env"
+)
+
+// Struct tags understood by Load.
+const (
+	tagEnv       = "env"
+	tagRequired  = "required"
+	tagDefault   = "default"
+	tagSeparator = "sep"
+	tagPrefix    = "prefix"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	urlType      = reflect.TypeOf(url.URL{})
+)
+
+// Load populates the struct pointed to by target from environment variables
+// described by struct tags, collecting every problem instead of stopping at
+// the first one.
+//
+// Supported tags:
+//   - env:"DB_HOST"       environment key to read (env:"-" skips the field)
+//   - required:"true"     records a MissingEnvError when the value is blank
+//   - default:"localhost" value used when the environment value is blank
+//   - sep:";"             separator for slice fields (defaults to ",")
+//   - prefix:"DB_"        prefix applied to the keys of a nested struct
+//
+// Supported field types are strings, booleans, signed and unsigned integers,
+// floats, time.Duration, url.URL / *url.URL, slices of those types and nested
+// structs (or pointers to structs), which are walked recursively.
+//
+// Example:
+//
+//	type AppConfig struct {
+//		Host    string        `env:"APP_HOST" required:"true"`
+//		Port    int           `env:"APP_PORT" default:"8080"`
+//		Timeout time.Duration `env:"APP_TIMEOUT" default:"30s"`
+//		DB      struct {
+//			Host string `env:"HOST" required:"true"`
+//		} `prefix:"DB_"`
+//	}
+//
+//	var cfg AppConfig
+//	if err := config.Load(&cfg); err != nil {
+//		return err
+//	}
+//
+// Returns:
+//   - error: a ValidationError listing every missing or malformed key, or a
+//     plain error when target is not a non-nil pointer to a struct
+func Load(target any) error {
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return errors.New("config: Load requires a non-nil pointer to a struct")
+	}
+
+	acc := &LoadAccumulator{}
+	loadStruct(acc, value.Elem(), "", value.Elem().Type().Name())
+	return acc.Err()
+}
+
+// loadStruct walks the fields of structValue, reading each tagged field from
+// the environment and descending into nested structs.
+func loadStruct(acc *LoadAccumulator, structValue reflect.Value, prefix, path string) {
+	structType := structValue.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldValue := structValue.Field(i)
+		fieldPath := field.Name
+		if path != "" {
+			fieldPath = path + "." + field.Name
+		}
+
+		key, hasKey := field.Tag.Lookup(tagEnv)
+		if key == "-" {
+			continue
+		}
+
+		if !hasKey {
+			if isNestedStruct(field.Type) {
+				if fieldValue.Kind() == reflect.Pointer {
+					if fieldValue.IsNil() {
+						fieldValue.Set(reflect.New(field.Type.Elem()))
+					}
+					fieldValue = fieldValue.Elem()
+				}
+				loadStruct(acc, fieldValue, prefix+field.Tag.Get(tagPrefix), fieldPath)
+			}
+			continue
+		}
+
+		key = prefix + key
+		context := "required by " + fieldPath
+
+		raw := strings.TrimSpace(env.GetString(key))
+		if raw == "" {
+			raw = field.Tag.Get(tagDefault)
+		}
+
+		if raw == "" {
+			if isTrue(field.Tag.Get(tagRequired)) {
+				acc.Add(MissingEnvError{Key: key, Context: context})
+			}
+			continue
+		}
+
+		separator := field.Tag.Get(tagSeparator)
+		if separator == "" {
+			separator = ","
+		}
+
+		if err := setFieldValue(fieldValue, raw, separator); err != nil {
+			acc.Add(InvalidEnvError{Key: key, Value: raw, Reason: err.Error()})
+		}
+	}
+}
+
+// isNestedStruct reports whether t is a struct (or pointer to struct) that
+// Load should walk rather than parse as a single value.
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != urlType
+}
+
+// isTrue reports whether a boolean struct tag value is set.
+func isTrue(tag string) bool {
+	enabled, err := strconv.ParseBool(strings.TrimSpace(tag))
+	return err == nil && enabled
+}
+
+// setFieldValue parses raw into the field according to its type. The
+// returned error text is used as the InvalidEnvError reason.
+func setFieldValue(field reflect.Value, raw, separator string) error {
+	if field.Kind() == reflect.Slice {
+		parts := strings.Split(raw, separator)
+		slice := reflect.MakeSlice(field.Type(), 0, len(parts))
+		for _, part := range parts {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			item := reflect.New(field.Type().Elem()).Elem()
+			if err := setScalarValue(item, part); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, item)
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	return setScalarValue(field, raw)
+}
+
+// setScalarValue parses raw into a single, non-slice field.
+func setScalarValue(field reflect.Value, raw string) error {
+	if field.Kind() == reflect.Pointer {
+		value := reflect.New(field.Type().Elem())
+		if err := setScalarValue(value.Elem(), raw); err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	}
+
+	switch field.Type() {
+	case durationType:
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return errors.New("must be a duration such as 30s or 5m")
+		}
+		field.SetInt(int64(duration))
+		return nil
+	case urlType:
+		parsed, err := url.Parse(raw)
+		if err != nil {
+			return errors.New("must be a URL")
+		}
+		field.Set(reflect.ValueOf(*parsed))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		parsed, err := parseBool(raw)
+		if err != nil {
+			return errors.New("must be a boolean")
+		}
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		parsed, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		parsed, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return errors.New("must be a non-negative integer")
+		}
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		parsed, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return errors.New("must be a number")
+		}
+		field.SetFloat(parsed)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package config
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type loadTestDatabase struct {
+	Host string `env:"HOST" required:"true"`
+	Port int    `env:"PORT" default:"5432"`
+}
+
+type loadTestConfig struct {
+	Name     string            `env:"LOAD_TEST_NAME" required:"true"`
+	Port     int               `env:"LOAD_TEST_PORT" default:"8080"`
+	Debug    bool              `env:"LOAD_TEST_DEBUG"`
+	Ratio    float64           `env:"LOAD_TEST_RATIO" default:"0.5"`
+	MaxConns uint16            `env:"LOAD_TEST_MAX_CONNS" default:"10"`
+	Timeout  time.Duration     `env:"LOAD_TEST_TIMEOUT" default:"30s"`
+	BaseURL  url.URL           `env:"LOAD_TEST_BASE_URL" default:"https://example.com"`
+	Callback *url.URL          `env:"LOAD_TEST_CALLBACK"`
+	Hosts    []string          `env:"LOAD_TEST_HOSTS"`
+	Weights  []int             `env:"LOAD_TEST_WEIGHTS" sep:";"`
+	Ignored  string            `env:"-"`
+	DB       loadTestDatabase  `prefix:"LOAD_TEST_DB_"`
+	Cache    *loadTestDatabase `prefix:"LOAD_TEST_CACHE_"`
+	internal string
+}
+
+func TestLoad_PopulatesFields(t *testing.T) {
+	t.Setenv("LOAD_TEST_NAME", "  app  ")
+	t.Setenv("LOAD_TEST_DEBUG", "yes")
+	t.Setenv("LOAD_TEST_CALLBACK", "https://example.com/callback")
+	t.Setenv("LOAD_TEST_HOSTS", "a.example.com, b.example.com,")
+	t.Setenv("LOAD_TEST_WEIGHTS", "1;2;3")
+	t.Setenv("LOAD_TEST_DB_HOST", "db.local")
+	t.Setenv("LOAD_TEST_DB_PORT", "6543")
+	t.Setenv("LOAD_TEST_CACHE_HOST", "cache.local")
+
+	var cfg loadTestConfig
+	if err := Load(&cfg); err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+
+	if cfg.Name != "app" {
+		t.Errorf("Name = %q, want %q", cfg.Name, "app")
+	}
+	if cfg.Port != 8080 {
+		t.Errorf("Port = %d, want 8080", cfg.Port)
+	}
+	if !cfg.Debug {
+		t.Error("Debug = false, want true")
+	}
+	if cfg.Ratio != 0.5 {
+		t.Errorf("Ratio = %v, want 0.5", cfg.Ratio)
+	}
+	if cfg.MaxConns != 10 {
+		t.Errorf("MaxConns = %d, want 10", cfg.MaxConns)
+	}
+	if cfg.Timeout != 30*time.Second {
+		t.Errorf("Timeout = %v, want 30s", cfg.Timeout)
+	}
+	if cfg.BaseURL.Host != "example.com" {
+		t.Errorf("BaseURL.Host = %q, want %q", cfg.BaseURL.Host, "example.com")
+	}
+	if cfg.Callback == nil || cfg.Callback.Path != "/callback" {
+		t.Errorf("Callback = %v, want path /callback", cfg.Callback)
+	}
+	if !reflect.DeepEqual(cfg.Hosts, []string{"a.example.com", "b.example.com"}) {
+		t.Errorf("Hosts = %v", cfg.Hosts)
+	}
+	if !reflect.DeepEqual(cfg.Weights, []int{1, 2, 3}) {
+		t.Errorf("Weights = %v", cfg.Weights)
+	}
+	if cfg.DB.Host != "db.local" || cfg.DB.Port != 6543 {
+		t.Errorf("DB = %+v", cfg.DB)
+	}
+	if cfg.Cache == nil || cfg.Cache.Host != "cache.local" || cfg.Cache.Port != 5432 {
+		t.Errorf("Cache = %+v", cfg.Cache)
+	}
+}
+
+func TestLoad_CollectsAllErrors(t *testing.T) {
+	t.Setenv("LOAD_TEST_NAME", "")
+	t.Setenv("LOAD_TEST_PORT", "not-a-number")
+	t.Setenv("LOAD_TEST_TIMEOUT", "forever")
+	t.Setenv("LOAD_TEST_DB_HOST", "")
+	t.Setenv("LOAD_TEST_CACHE_HOST", "cache.local")
+
+	var cfg loadTestConfig
+	err := Load(&cfg)
+	if err == nil {
+		t.Fatal("Load() expected error but got none")
+	}
+
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Load() error type = %T, want ValidationError", err)
+	}
+
+	if got := len(validationErr.Errors()); got != 4 {
+		t.Fatalf("Load() collected %d errors, want 4: %v", got, err)
+	}
+
+	missing := 0
+	for _, e := range validationErr.Errors() {
+		var missingErr MissingEnvError
+		if errors.As(e, &missingErr) {
+			missing++
+		}
+	}
+	if missing != 2 {
+		t.Errorf("Load() reported %d missing keys, want 2", missing)
+	}
+
+	invalid := 0
+	for _, e := range validationErr.Errors() {
+		var invalidErr InvalidEnvError
+		if errors.As(e, &invalidErr) {
+			invalid++
+		}
+	}
+	if invalid != 2 {
+		t.Errorf("Load() reported %d invalid keys, want 2", invalid)
+	}
+
+	for _, key := range []string{"LOAD_TEST_NAME", "LOAD_TEST_PORT", "LOAD_TEST_TIMEOUT", "LOAD_TEST_DB_HOST"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("Load() error does not mention %s: %v", key, err)
+		}
+	}
+}
+
+func TestLoad_InvalidTarget(t *testing.T) {
+	var cfg loadTestConfig
+	var nilPtr *loadTestConfig
+	value := 1
+
+	for name, target := range map[string]any{
+		"nil":            nil,
+		"non-pointer":    cfg,
+		"nil pointer":    nilPtr,
+		"pointer to int": &value,
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := Load(target)
+			if err == nil {
+				t.Fatal("Load() expected error but got none")
+			}
+			var validationErr ValidationError
+			if errors.As(err, &validationErr) {
+				t.Errorf("Load() returned ValidationError for invalid target")
+			}
+		})
+	}
+}
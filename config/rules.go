@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dracory/env"
+)
+
+// RuleError describes a violated cross-field rule.
+type RuleError struct {
+	Keys    []string
+	Message string
+}
+
+// Error returns the formatted error naming the keys involved.
+func (e RuleError) Error() string {
+	quoted := make([]string, 0, len(e.Keys))
+	for _, key := range e.Keys {
+		quoted = append(quoted, fmt.Sprintf("%q", key))
+	}
+
+	return fmt.Sprintf("config: envs %s: %s", strings.Join(quoted, ", "), e.Message)
+}
+
+// Rule is a validation rule spanning several environment keys. Rules are
+// built with RequireOneOf, RequireAllOrNone, MutuallyExclusive and
+// RequireRule, can be made conditional with When, and are evaluated by
+// Check or LoadAccumulator.MustSatisfy.
+type Rule struct {
+	keys  []string
+	check func(values map[string]string) string
+}
+
+// Keys returns the keys the rule inspects.
+func (r Rule) Keys() []string {
+	return append([]string(nil), r.keys...)
+}
+
+// Check evaluates the rule against the process environment.
+func (r Rule) Check() error {
+	return r.Evaluate(func(key string) string {
+		return env.GetString(key)
+	})
+}
+
+// Evaluate evaluates the rule against values returned by lookup, which
+// allows checking a ConfigInterface or a map instead of the environment.
+// Values are trimmed and blank values count as unset.
+func (r Rule) Evaluate(lookup func(key string) string) error {
+	if r.check == nil {
+		return nil
+	}
+
+	values := make(map[string]string, len(r.keys))
+	for _, key := range r.keys {
+		values[key] = strings.TrimSpace(lookup(key))
+	}
+
+	if message := r.check(values); message != "" {
+		return RuleError{Keys: r.Keys(), Message: message}
+	}
+
+	return nil
+}
+
+// RequireOneOf requires at least one of keys to be set, e.g. an SMTP host
+// or a mail API key.
+func RequireOneOf(keys ...string) Rule {
+	return Rule{keys: keys, check: func(values map[string]string) string {
+		if len(setKeys(keys, values)) == 0 {
+			return "at least one must be set"
+		}
+		return ""
+	}}
+}
+
+// RequireAllOrNone requires keys to be set together or not at all, e.g. a
+// TLS certificate and its private key.
+func RequireAllOrNone(keys ...string) Rule {
+	return Rule{keys: keys, check: func(values map[string]string) string {
+		set := setKeys(keys, values)
+		if len(set) == 0 || len(set) == len(keys) {
+			return ""
+		}
+
+		missing := []string{}
+		for _, key := range keys {
+			if values[key] == "" {
+				missing = append(missing, key)
+			}
+		}
+		return "must be set together or not at all (missing " + strings.Join(missing, ", ") + ")"
+	}}
+}
+
+// MutuallyExclusive allows at most one of keys to be set.
+func MutuallyExclusive(keys ...string) Rule {
+	return Rule{keys: keys, check: func(values map[string]string) string {
+		set := setKeys(keys, values)
+		if len(set) <= 1 {
+			return ""
+		}
+		return "at most one may be set (set: " + strings.Join(set, ", ") + ")"
+	}}
+}
+
+// RequireRule builds a custom rule over keys. The predicate receives the
+// trimmed values of keys (blank when unset) and returns false to report
+// message.
+func RequireRule(message string, predicate func(values map[string]string) bool, keys ...string) Rule {
+	return Rule{keys: keys, check: func(values map[string]string) string {
+		if predicate(values) {
+			return ""
+		}
+		return message
+	}}
+}
+
+// When makes rule apply only when condition is true.
+func When(condition bool, rule Rule) Rule {
+	if condition {
+		return rule
+	}
+	return Rule{keys: rule.keys}
+}
+
+// setKeys returns the keys, in order, whose values are non-blank.
+func setKeys(keys []string, values map[string]string) []string {
+	set := []string{}
+	for _, key := range keys {
+		if values[key] != "" {
+			set = append(set, key)
+		}
+	}
+	return set
+}
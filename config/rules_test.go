@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRules(t *testing.T) {
+	values := map[string]string{
+		"SMTP_HOST":     "smtp.example.com",
+		"TLS_CERT_FILE": "cert.pem",
+		"TLS_KEY_FILE":  " ",
+		"DB_DSN":        "postgres://",
+		"DB_HOST":       "db",
+	}
+	lookup := func(key string) string { return values[key] }
+
+	tests := []struct {
+		name    string
+		rule    Rule
+		message string
+	}{
+		{"one of satisfied", RequireOneOf("SMTP_HOST", "MAIL_API_KEY"), ""},
+		{"one of violated", RequireOneOf("MAIL_API_KEY", "SENDGRID_KEY"), "at least one must be set"},
+		{"all or none partial", RequireAllOrNone("TLS_CERT_FILE", "TLS_KEY_FILE"), "must be set together or not at all (missing TLS_KEY_FILE)"},
+		{"all or none empty", RequireAllOrNone("A_UNSET", "B_UNSET"), ""},
+		{"exclusive violated", MutuallyExclusive("DB_DSN", "DB_HOST"), "at most one may be set (set: DB_DSN, DB_HOST)"},
+		{"exclusive satisfied", MutuallyExclusive("DB_DSN", "DB_SOCKET"), ""},
+		{"predicate violated", RequireRule("must match", func(v map[string]string) bool { return v["SMTP_HOST"] == v["DB_HOST"] }, "SMTP_HOST", "DB_HOST"), "must match"},
+		{"when false", When(false, RequireOneOf("MAIL_API_KEY")), ""},
+		{"when true", When(true, RequireOneOf("MAIL_API_KEY")), "at least one must be set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule.Evaluate(lookup)
+			if tt.message == "" {
+				if err != nil {
+					t.Errorf("Evaluate() = %v, want nil", err)
+				}
+				return
+			}
+
+			var ruleErr RuleError
+			if !errors.As(err, &ruleErr) {
+				t.Fatalf("Evaluate() error = %v, want RuleError", err)
+			}
+			if ruleErr.Message != tt.message {
+				t.Errorf("Message = %q, want %q", ruleErr.Message, tt.message)
+			}
+			if !reflect.DeepEqual(ruleErr.Keys, tt.rule.Keys()) {
+				t.Errorf("Keys = %v, want %v", ruleErr.Keys, tt.rule.Keys())
+			}
+		})
+	}
+}
+
+func TestLoadAccumulator_MustSatisfy(t *testing.T) {
+	t.Setenv("RULES_TEST_CERT", "cert.pem")
+	t.Setenv("RULES_TEST_KEY", "")
+	t.Setenv("RULES_TEST_DSN", "dsn")
+	t.Setenv("RULES_TEST_HOST", "host")
+
+	acc := &LoadAccumulator{}
+	acc.MustSatisfy(
+		RequireOneOf("RULES_TEST_SMTP", "RULES_TEST_API"),
+		RequireAllOrNone("RULES_TEST_CERT", "RULES_TEST_KEY"),
+		MutuallyExclusive("RULES_TEST_DSN", "RULES_TEST_HOST"),
+		RequireOneOf("RULES_TEST_DSN", "RULES_TEST_HOST"),
+	)
+
+	var validationErr ValidationError
+	if !errors.As(acc.Err(), &validationErr) {
+		t.Fatalf("Err() = %v, want ValidationError", acc.Err())
+	}
+	if got := len(validationErr.Errors()); got != 3 {
+		t.Fatalf("got %d errors, want 3: %v", got, validationErr)
+	}
+
+	message := validationErr.Error()
+	for _, want := range []string{
+		`config: envs "RULES_TEST_SMTP", "RULES_TEST_API": at least one must be set`,
+		"(missing RULES_TEST_KEY)",
+		"(set: RULES_TEST_DSN, RULES_TEST_HOST)",
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("error missing %q:\n%s", want, message)
+		}
+	}
+
+	if got := acc.Keys(); len(got) != 6 {
+		t.Errorf("Keys() = %v, want the 6 distinct rule keys", got)
+	}
+}
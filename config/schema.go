@@ -0,0 +1,368 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Key types understood by Schema.
+const (
+	KeyTypeString   = "string"
+	KeyTypeInt      = "int"
+	KeyTypeFloat    = "float"
+	KeyTypeBool     = "bool"
+	KeyTypeDuration = "duration"
+	KeyTypeURL      = "url"
+	KeyTypePort     = "port"
+)
+
+// KeySpec declares one configuration key read by the application.
+type KeySpec struct {
+	// Key is the environment variable name, e.g. "DB_HOST"
+	Key string
+
+	// Type is one of the KeyType* constants (defaults to KeyTypeString)
+	Type string
+
+	// Required marks keys that must be set
+	Required bool
+
+	// Secret marks keys whose values must never be printed
+	Secret bool
+
+	// Default is the value used when the key is not set
+	Default string
+
+	// Enum lists the allowed values, if restricted
+	Enum []string
+
+	// Description explains what the key configures
+	Description string
+}
+
+// UnknownEnvError describes a key that is set but not declared in the
+// schema, with an optional suggestion for a likely typo.
+type UnknownEnvError struct {
+	Key        string
+	Suggestion string
+}
+
+// Error returns the formatted error describing the unknown key.
+func (e UnknownEnvError) Error() string {
+	if e.Suggestion == "" {
+		return fmt.Sprintf("config: env %q is not declared", e.Key)
+	}
+
+	return fmt.Sprintf(
+		"config: env %q is not declared (did you mean %q?)",
+		e.Key,
+		e.Suggestion,
+	)
+}
+
+// Schema is the single place declaring which keys an application reads.
+type Schema struct {
+	specs []KeySpec
+}
+
+// NewSchema creates an empty schema; declare keys with Register.
+func NewSchema() *Schema {
+	return &Schema{}
+}
+
+// Register adds specs to the schema. Keys must be non-blank and unique.
+func (s *Schema) Register(specs ...KeySpec) error {
+	for _, spec := range specs {
+		if strings.TrimSpace(spec.Key) == "" {
+			return errors.New("config: schema key cannot be empty")
+		}
+		if _, exists := s.Spec(spec.Key); exists {
+			return fmt.Errorf("config: schema key %q is already registered", spec.Key)
+		}
+		if spec.Type == "" {
+			spec.Type = KeyTypeString
+		}
+		if _, known := jsonSchemaTypes[spec.Type]; !known {
+			return fmt.Errorf("config: schema key %q has unknown type %q", spec.Key, spec.Type)
+		}
+		s.specs = append(s.specs, spec)
+	}
+	return nil
+}
+
+// Specs returns the registered specs in registration order.
+func (s *Schema) Specs() []KeySpec {
+	return append([]KeySpec(nil), s.specs...)
+}
+
+// Spec returns the spec registered for key.
+func (s *Schema) Spec(key string) (KeySpec, bool) {
+	for _, spec := range s.specs {
+		if spec.Key == key {
+			return spec, true
+		}
+	}
+	return KeySpec{}, false
+}
+
+// Keys returns the registered keys in registration order.
+func (s *Schema) Keys() []string {
+	keys := make([]string, 0, len(s.specs))
+	for _, spec := range s.specs {
+		keys = append(keys, spec.Key)
+	}
+	return keys
+}
+
+// SecretKeys returns the keys marked as secret, for use in DumpOptions.
+func (s *Schema) SecretKeys() []string {
+	keys := []string{}
+	for _, spec := range s.specs {
+		if spec.Secret {
+			keys = append(keys, spec.Key)
+		}
+	}
+	return keys
+}
+
+// jsonSchemaTypes maps key types to their JSON Schema fragments.
+var jsonSchemaTypes = map[string]map[string]any{
+	KeyTypeString:   {"type": "string"},
+	KeyTypeInt:      {"type": "integer"},
+	KeyTypeFloat:    {"type": "number"},
+	KeyTypeBool:     {"type": "boolean"},
+	KeyTypeDuration: {"type": "string", "pattern": `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`},
+	KeyTypeURL:      {"type": "string", "format": "uri"},
+	KeyTypePort:     {"type": "integer", "minimum": 1, "maximum": 65535},
+}
+
+// JSONSchema renders the schema as a JSON Schema (draft 2020-12) document
+// describing a configuration object, such as the file read by
+// NewJSONFileSource. Secret keys are marked writeOnly and their defaults are
+// omitted.
+func (s *Schema) JSONSchema() ([]byte, error) {
+	properties := map[string]any{}
+	required := []string{}
+
+	for _, spec := range s.specs {
+		property := map[string]any{}
+		for k, v := range jsonSchemaTypes[spec.Type] {
+			property[k] = v
+		}
+		if spec.Description != "" {
+			property["description"] = spec.Description
+		}
+		if spec.Default != "" && !spec.Secret {
+			property["default"] = jsonSchemaDefault(spec)
+		}
+		if len(spec.Enum) > 0 {
+			property["enum"] = spec.Enum
+		}
+		if spec.Secret {
+			property["writeOnly"] = true
+		}
+		properties[spec.Key] = property
+
+		if spec.Required {
+			required = append(required, spec.Key)
+		}
+	}
+
+	document := map[string]any{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+
+	return json.MarshalIndent(document, "", "  ")
+}
+
+// jsonSchemaDefault converts the default of spec to the JSON type of the
+// key, falling back to the raw string when it does not parse.
+func jsonSchemaDefault(spec KeySpec) any {
+	switch spec.Type {
+	case KeyTypeInt, KeyTypePort:
+		if value, err := strconv.Atoi(spec.Default); err == nil {
+			return value
+		}
+	case KeyTypeFloat:
+		if value, err := strconv.ParseFloat(spec.Default, 64); err == nil {
+			return value
+		}
+	case KeyTypeBool:
+		if value, err := parseBool(spec.Default); err == nil {
+			return value
+		}
+	}
+	return spec.Default
+}
+
+// Markdown renders the schema as a Markdown table. The defaults of secret
+// keys are omitted.
+func (s *Schema) Markdown() string {
+	var builder strings.Builder
+	builder.WriteString("| Key | Type | Required | Default | Description |\n")
+	builder.WriteString("| --- | --- | --- | --- | --- |\n")
+
+	for _, spec := range s.specs {
+		required := "no"
+		if spec.Required {
+			required = "yes"
+		}
+
+		description := spec.Description
+		if len(spec.Enum) > 0 {
+			description = strings.TrimSpace(description + " One of: `" + strings.Join(spec.Enum, "`, `") + "`.")
+		}
+		if spec.Secret {
+			description = strings.TrimSpace(description + " (secret)")
+		}
+
+		defaultValue := ""
+		if spec.Default != "" && !spec.Secret {
+			defaultValue = "`" + spec.Default + "`"
+		}
+
+		fmt.Fprintf(&builder, "| `%s` | %s | %s | %s | %s |\n",
+			spec.Key,
+			spec.Type,
+			required,
+			defaultValue,
+			strings.ReplaceAll(description, "|", `\|`),
+		)
+	}
+
+	return builder.String()
+}
+
+// EnvExample renders the schema as a ".env.example" file. Secret keys are
+// always left blank.
+func (s *Schema) EnvExample() string {
+	var builder strings.Builder
+
+	for i, spec := range s.specs {
+		if i > 0 {
+			builder.WriteByte('\n')
+		}
+
+		if spec.Description != "" {
+			fmt.Fprintf(&builder, "# %s\n", spec.Description)
+		}
+
+		attributes := []string{spec.Type}
+		if spec.Required {
+			attributes = append(attributes, "required")
+		}
+		if spec.Secret {
+			attributes = append(attributes, "secret")
+		}
+		if len(spec.Enum) > 0 {
+			attributes = append(attributes, "one of: "+strings.Join(spec.Enum, ", "))
+		}
+		fmt.Fprintf(&builder, "# (%s)\n", strings.Join(attributes, ", "))
+
+		value := spec.Default
+		if spec.Secret {
+			value = ""
+		}
+		fmt.Fprintf(&builder, "%s=%s\n", spec.Key, value)
+	}
+
+	return builder.String()
+}
+
+// CheckUnknown flags every key in keys that is not declared in the schema,
+// suggesting the closest declared key for likely typos.
+//
+// Returns:
+//   - error: a ValidationError of UnknownEnvError values, or nil
+func (s *Schema) CheckUnknown(keys []string) error {
+	acc := &LoadAccumulator{}
+	declared := s.Keys()
+
+	for _, key := range keys {
+		if slices.Contains(declared, key) {
+			continue
+		}
+		acc.Add(UnknownEnvError{Key: key, Suggestion: suggestKey(key, declared)})
+	}
+
+	return acc.Err()
+}
+
+// EnvironmentKeys returns the names of the process environment variables
+// starting with any of the prefixes, for use with Schema.CheckUnknown. With
+// no prefixes, like NewEnvSource(""), every variable is returned.
+func EnvironmentKeys(prefixes ...string) []string {
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+
+	keys := []string{}
+	for _, pair := range os.Environ() {
+		key, _, _ := strings.Cut(pair, "=")
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// suggestKey returns the declared key closest to key, or an empty string
+// when none is close enough to be a plausible typo.
+func suggestKey(key string, declared []string) string {
+	best := ""
+	bestDistance := 0
+	upperKey := strings.ToUpper(key)
+
+	for _, candidate := range declared {
+		distance := levenshtein(upperKey, strings.ToUpper(candidate))
+		if best == "" || distance < bestDistance {
+			best = candidate
+			bestDistance = distance
+		}
+	}
+
+	maxDistance := max(2, len(key)/4)
+	if best == "" || bestDistance > maxDistance {
+		return ""
+	}
+
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(br)]
+}
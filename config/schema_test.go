@@ -0,0 +1,188 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func newTestSchema(t *testing.T) *Schema {
+	t.Helper()
+
+	schema := NewSchema()
+	err := schema.Register(
+		KeySpec{Key: "APP_HOST", Required: true, Description: "Public host name"},
+		KeySpec{Key: "APP_PORT", Type: KeyTypePort, Default: "8080", Description: "HTTP port"},
+		KeySpec{Key: "APP_DEBUG", Type: KeyTypeBool, Default: "false"},
+		KeySpec{Key: "LOG_LEVEL", Enum: []string{"debug", "info"}, Default: "info", Description: "Log verbosity"},
+		KeySpec{Key: "DB_PASSWORD", Required: true, Secret: true, Default: "changeme", Description: "Database password"},
+	)
+	if err != nil {
+		t.Fatalf("Register() unexpected error: %v", err)
+	}
+
+	return schema
+}
+
+func TestSchema_Register(t *testing.T) {
+	schema := newTestSchema(t)
+
+	if got := schema.Keys(); !reflect.DeepEqual(got, []string{"APP_HOST", "APP_PORT", "APP_DEBUG", "LOG_LEVEL", "DB_PASSWORD"}) {
+		t.Errorf("Keys() = %v", got)
+	}
+
+	spec, found := schema.Spec("APP_HOST")
+	if !found || spec.Type != KeyTypeString {
+		t.Errorf("Spec(APP_HOST) = %+v, %v; want default string type", spec, found)
+	}
+
+	if got := schema.SecretKeys(); !reflect.DeepEqual(got, []string{"DB_PASSWORD"}) {
+		t.Errorf("SecretKeys() = %v", got)
+	}
+
+	for name, spec := range map[string]KeySpec{
+		"empty key":    {Key: " "},
+		"duplicate":    {Key: "APP_HOST"},
+		"unknown type": {Key: "NEW_KEY", Type: "uuid"},
+	} {
+		if err := schema.Register(spec); err == nil {
+			t.Errorf("Register() %s should fail", name)
+		}
+	}
+}
+
+func TestSchema_JSONSchema(t *testing.T) {
+	data, err := newTestSchema(t).JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema() unexpected error: %v", err)
+	}
+
+	var document map[string]any
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatalf("JSONSchema() produced invalid JSON: %v", err)
+	}
+
+	if document["type"] != "object" || document["additionalProperties"] != false {
+		t.Errorf("unexpected document header: %v", document)
+	}
+
+	if !reflect.DeepEqual(document["required"], []any{"APP_HOST", "DB_PASSWORD"}) {
+		t.Errorf("required = %v", document["required"])
+	}
+
+	properties := document["properties"].(map[string]any)
+
+	port := properties["APP_PORT"].(map[string]any)
+	if port["type"] != "integer" || port["maximum"] != float64(65535) || port["default"] != float64(8080) {
+		t.Errorf("APP_PORT = %v", port)
+	}
+
+	if debug := properties["APP_DEBUG"].(map[string]any); debug["default"] != false {
+		t.Errorf("APP_DEBUG default = %v, want false", debug["default"])
+	}
+
+	if level := properties["LOG_LEVEL"].(map[string]any); !reflect.DeepEqual(level["enum"], []any{"debug", "info"}) {
+		t.Errorf("LOG_LEVEL enum = %v", level["enum"])
+	}
+
+	if password := properties["DB_PASSWORD"].(map[string]any); password["writeOnly"] != true {
+		t.Errorf("DB_PASSWORD should be writeOnly: %v", password)
+	}
+
+	if strings.Contains(string(data), "changeme") {
+		t.Error("JSONSchema() leaked a secret default")
+	}
+}
+
+func TestSchema_Markdown(t *testing.T) {
+	markdown := newTestSchema(t).Markdown()
+
+	for _, want := range []string{
+		"| Key | Type | Required | Default | Description |",
+		"| `APP_HOST` | string | yes |  | Public host name |",
+		"| `APP_PORT` | port | no | `8080` | HTTP port |",
+		"One of: `debug`, `info`.",
+		"Database password (secret)",
+	} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Markdown() missing %q:\n%s", want, markdown)
+		}
+	}
+
+	if strings.Contains(markdown, "changeme") {
+		t.Error("Markdown() leaked a secret default")
+	}
+}
+
+func TestSchema_EnvExample(t *testing.T) {
+	example := newTestSchema(t).EnvExample()
+
+	for _, want := range []string{
+		"# Public host name\n# (string, required)\nAPP_HOST=\n",
+		"# HTTP port\n# (port)\nAPP_PORT=8080\n",
+		"# (string, one of: debug, info)\nLOG_LEVEL=info\n",
+		"# (string, required, secret)\nDB_PASSWORD=\n",
+	} {
+		if !strings.Contains(example, want) {
+			t.Errorf("EnvExample() missing %q:\n%s", want, example)
+		}
+	}
+
+	if strings.Contains(example, "changeme") {
+		t.Error("EnvExample() leaked a secret default")
+	}
+}
+
+func TestSchema_CheckUnknown(t *testing.T) {
+	schema := newTestSchema(t)
+
+	if err := schema.CheckUnknown([]string{"APP_HOST", "DB_PASSWORD"}); err != nil {
+		t.Errorf("CheckUnknown() with declared keys = %v, want nil", err)
+	}
+
+	err := schema.CheckUnknown([]string{"APP_HOTS", "DB_PASWORD", "APP_PORT", "COMPLETELY_DIFFERENT"})
+
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("CheckUnknown() error type = %T, want ValidationError", err)
+	}
+
+	want := []UnknownEnvError{
+		{Key: "APP_HOTS", Suggestion: "APP_HOST"},
+		{Key: "DB_PASWORD", Suggestion: "DB_PASSWORD"},
+		{Key: "COMPLETELY_DIFFERENT"},
+	}
+
+	errs := validationErr.Errors()
+	if len(errs) != len(want) {
+		t.Fatalf("CheckUnknown() returned %d errors, want %d: %v", len(errs), len(want), err)
+	}
+	for i := range want {
+		if errs[i] != want[i] {
+			t.Errorf("error %d = %#v, want %#v", i, errs[i], want[i])
+		}
+	}
+
+	if !strings.Contains(err.Error(), `did you mean "APP_HOST"?`) {
+		t.Errorf("error message missing suggestion: %v", err)
+	}
+}
+
+func TestEnvironmentKeys(t *testing.T) {
+	t.Setenv("SCHEMA_TEST_B", "1")
+	t.Setenv("SCHEMA_TEST_A", "1")
+	t.Setenv("OTHER_SCHEMA_TEST", "1")
+
+	got := EnvironmentKeys("SCHEMA_TEST_")
+	if !reflect.DeepEqual(got, []string{"SCHEMA_TEST_A", "SCHEMA_TEST_B"}) {
+		t.Errorf("EnvironmentKeys() = %v", got)
+	}
+
+	all := EnvironmentKeys()
+	if !slices.Contains(all, "SCHEMA_TEST_A") || !slices.Contains(all, "OTHER_SCHEMA_TEST") {
+		t.Errorf("EnvironmentKeys() without prefixes = %v, want every variable", all)
+	}
+}
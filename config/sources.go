@@ -0,0 +1,172 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// Well-known source names recorded in Provenance.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceDotEnv  = "dotenv"
+	SourceVault   = "vault"
+	SourceEnv     = "env"
+	SourceArgs    = "args"
+)
+
+// SourceInterface represents a single layer of configuration values.
+type SourceInterface interface {
+	// Name identifies the source in Provenance (e.g. "env", "vault")
+	Name() string
+
+	// Values returns the key/value pairs supplied by the source
+	Values() (map[string]any, error)
+}
+
+// source is a function-backed implementation of SourceInterface
+type source struct {
+	name   string
+	values func() (map[string]any, error)
+}
+
+// NewSource creates a custom configuration source from a loader function.
+func NewSource(name string, values func() (map[string]any, error)) SourceInterface {
+	return &source{name: name, values: values}
+}
+
+// Name returns the source name
+func (s *source) Name() string {
+	return s.name
+}
+
+// Values returns the key/value pairs supplied by the source
+func (s *source) Values() (map[string]any, error) {
+	if s.values == nil {
+		return map[string]any{}, nil
+	}
+	return s.values()
+}
+
+// NewDefaultsSource creates a source that supplies fixed default values.
+func NewDefaultsSource(defaults map[string]any) SourceInterface {
+	return NewSource(SourceDefault, func() (map[string]any, error) {
+		values := make(map[string]any, len(defaults))
+		for k, v := range defaults {
+			values[k] = v
+		}
+		return values, nil
+	})
+}
+
+// NewJSONFileSource creates a source that reads the top-level keys of a JSON
+// object stored in the file at path.
+func NewJSONFileSource(path string) SourceInterface {
+	return NewSource(SourceFile, func() (map[string]any, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		values := map[string]any{}
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+
+		return values, nil
+	})
+}
+
+// NewDotEnvSource creates a source that reads one or more .env files. Files
+// are applied in order, so later files override earlier ones. The process
+// environment is not modified.
+func NewDotEnvSource(paths ...string) SourceInterface {
+	return NewSource(SourceDotEnv, func() (map[string]any, error) {
+		values := map[string]any{}
+		for _, path := range paths {
+			pairs, err := godotenv.Read(path)
+			if err != nil {
+				return nil, err
+			}
+			for k, v := range pairs {
+				values[k] = v
+			}
+		}
+		return values, nil
+	})
+}
+
+// NewVaultFileSource creates a source that decrypts the EnvEnc vault file at
+// vaultFilePath using the key derived from publicKey and privateKey. The
+// process environment is not modified.
+func NewVaultFileSource(vaultFilePath, publicKey, privateKey string) SourceInterface {
+	return NewSource(SourceVault, func() (map[string]any, error) {
+		if _, err := os.Stat(vaultFilePath); err != nil {
+			return nil, err
+		}
+
+		keys, err := NewVault(vaultFilePath, publicKey, privateKey).List()
+		if err != nil {
+			return nil, err
+		}
+
+		values := make(map[string]any, len(keys))
+		for k, v := range keys {
+			values[k] = v
+		}
+		return values, nil
+	})
+}
+
+// NewEnvSource creates a source from the process environment. Only variables
+// whose names start with prefix are included; an empty prefix includes all.
+// The prefix is kept in the resulting keys.
+func NewEnvSource(prefix string) SourceInterface {
+	return NewSource(SourceEnv, func() (map[string]any, error) {
+		values := map[string]any{}
+		for _, pair := range os.Environ() {
+			key, value, found := strings.Cut(pair, "=")
+			if !found || !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			values[key] = value
+		}
+		return values, nil
+	})
+}
+
+// NewArgsSource creates a source from command-line overrides of the form
+// "--KEY=value". Arguments in any other form are ignored.
+func NewArgsSource(args []string) SourceInterface {
+	return NewSource(SourceArgs, func() (map[string]any, error) {
+		values := map[string]any{}
+		for _, arg := range args {
+			if !strings.HasPrefix(arg, "--") {
+				continue
+			}
+			key, value, found := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+			if !found || key == "" {
+				continue
+			}
+			values[key] = value
+		}
+		return values, nil
+	})
+}
+
+// OptionalSource wraps a source so that a missing file is treated as an
+// empty layer instead of an error.
+func OptionalSource(src SourceInterface) SourceInterface {
+	return NewSource(src.Name(), func() (map[string]any, error) {
+		values, err := src.Values()
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]any{}, nil
+		}
+		return values, err
+	})
+}
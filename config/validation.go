@@ -31,7 +31,17 @@ func (e MissingEnvError) Error() string {
 // RequireString trims and retrieves the environment value for the
 // provided key, returning a typed MissingEnvError when the value is absent.
 func RequireString(key, context string) (string, error) {
-	value := strings.TrimSpace(env.GetString(key))
+	return requireString(envLookup, key, context)
+}
+
+// envLookup reads key from the process environment.
+func envLookup(key string) string {
+	return env.GetString(key)
+}
+
+// requireString is RequireString reading values through lookup.
+func requireString(lookup func(key string) string, key, context string) (string, error) {
+	value := strings.TrimSpace(lookup(key))
 
 	if err := EnsureRequired(value, key, context); err != nil {
 		return "", err
@@ -59,3 +69,22 @@ func EnsureRequired(value, key, context string) error {
 
 	return MissingEnvError{Key: key, Context: context}
 }
+
+// InvalidEnvError describes an environment variable that is present
+// but whose value cannot be parsed or falls outside the allowed values.
+type InvalidEnvError struct {
+	Key    string
+	Value  string
+	Reason string
+}
+
+// Error returns the formatted error describing the invalid
+// environment variable and the reason it was rejected.
+func (e InvalidEnvError) Error() string {
+	return fmt.Sprintf(
+		"config: env %q has invalid value %q: %s",
+		e.Key,
+		e.Value,
+		e.Reason,
+	)
+}
@@ -0,0 +1,212 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RequireInt retrieves the environment value for the provided key and
+// parses it as an integer, returning a MissingEnvError when the value is
+// absent or an InvalidEnvError when it is not a valid integer.
+func RequireInt(key, context string) (int, error) {
+	return requireInt(envLookup, key, context)
+}
+
+// requireInt is RequireInt reading values through lookup.
+func requireInt(lookup func(key string) string, key, context string) (int, error) {
+	value, err := requireString(lookup, key, context)
+	if err != nil {
+		return 0, err
+	}
+
+	return parseInt(key, value)
+}
+
+// parseInt parses the raw value of key as an integer.
+func parseInt(key, value string) (int, error) {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, InvalidEnvError{Key: key, Value: value, Reason: "must be an integer"}
+	}
+
+	return parsed, nil
+}
+
+// RequireIntInRange behaves like RequireInt and additionally checks that
+// the value lies within the inclusive range [min, max].
+func RequireIntInRange(key, context string, min, max int) (int, error) {
+	return requireIntInRange(envLookup, key, context, min, max)
+}
+
+// requireIntInRange is RequireIntInRange reading values through lookup.
+func requireIntInRange(lookup func(key string) string, key, context string, min, max int) (int, error) {
+	raw, err := requireString(lookup, key, context)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := parseInt(key, raw)
+	if err != nil {
+		return 0, err
+	}
+
+	if value < min || value > max {
+		return 0, InvalidEnvError{
+			Key:    key,
+			Value:  raw,
+			Reason: fmt.Sprintf("must be between %d and %d", min, max),
+		}
+	}
+
+	return value, nil
+}
+
+// RequirePort retrieves the environment value for the provided key as a
+// TCP/UDP port number in the range 1-65535.
+func RequirePort(key, context string) (int, error) {
+	return requirePort(envLookup, key, context)
+}
+
+// requirePort is RequirePort reading values through lookup.
+func requirePort(lookup func(key string) string, key, context string) (int, error) {
+	return requireIntInRange(lookup, key, context, 1, 65535)
+}
+
+// RequireBool retrieves the environment value for the provided key as a
+// boolean. Accepted values are those understood by strconv.ParseBool plus
+// "yes", "no", "on" and "off" (case-insensitive).
+func RequireBool(key, context string) (bool, error) {
+	return requireBool(envLookup, key, context)
+}
+
+// requireBool is RequireBool reading values through lookup.
+func requireBool(lookup func(key string) string, key, context string) (bool, error) {
+	value, err := requireString(lookup, key, context)
+	if err != nil {
+		return false, err
+	}
+
+	parsed, err := parseBool(value)
+	if err != nil {
+		return false, InvalidEnvError{Key: key, Value: value, Reason: "must be a boolean"}
+	}
+
+	return parsed, nil
+}
+
+// RequireDuration retrieves the environment value for the provided key as a
+// time.Duration (e.g. "30s", "5m", "1h30m").
+func RequireDuration(key, context string) (time.Duration, error) {
+	return requireDuration(envLookup, key, context)
+}
+
+// requireDuration is RequireDuration reading values through lookup.
+func requireDuration(lookup func(key string) string, key, context string) (time.Duration, error) {
+	value, err := requireString(lookup, key, context)
+	if err != nil {
+		return 0, err
+	}
+
+	return parseDuration(key, value)
+}
+
+// parseDuration parses the raw value of key as a time.Duration.
+func parseDuration(key, value string) (time.Duration, error) {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, InvalidEnvError{Key: key, Value: value, Reason: "must be a duration such as 30s or 5m"}
+	}
+
+	return parsed, nil
+}
+
+// RequireDurationInRange behaves like RequireDuration and additionally
+// checks that the value lies within the inclusive range [min, max].
+func RequireDurationInRange(key, context string, min, max time.Duration) (time.Duration, error) {
+	return requireDurationInRange(envLookup, key, context, min, max)
+}
+
+// requireDurationInRange is RequireDurationInRange reading values through lookup.
+func requireDurationInRange(lookup func(key string) string, key, context string, min, max time.Duration) (time.Duration, error) {
+	raw, err := requireString(lookup, key, context)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := parseDuration(key, raw)
+	if err != nil {
+		return 0, err
+	}
+
+	if value < min || value > max {
+		return 0, InvalidEnvError{
+			Key:    key,
+			Value:  raw,
+			Reason: fmt.Sprintf("must be between %s and %s", min, max),
+		}
+	}
+
+	return value, nil
+}
+
+// RequireURL retrieves the environment value for the provided key as an
+// absolute URL, i.e. one with both a scheme and a host.
+func RequireURL(key, context string) (*url.URL, error) {
+	return requireURL(envLookup, key, context)
+}
+
+// requireURL is RequireURL reading values through lookup.
+func requireURL(lookup func(key string) string, key, context string) (*url.URL, error) {
+	value, err := requireString(lookup, key, context)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, InvalidEnvError{Key: key, Value: value, Reason: "must be an absolute URL"}
+	}
+
+	return parsed, nil
+}
+
+// RequireEnum retrieves the environment value for the provided key and
+// checks that it is one of the allowed values (case-sensitive).
+func RequireEnum(key, context string, allowed ...string) (string, error) {
+	return requireEnum(envLookup, key, context, allowed...)
+}
+
+// requireEnum is RequireEnum reading values through lookup.
+func requireEnum(lookup func(key string) string, key, context string, allowed ...string) (string, error) {
+	value, err := requireString(lookup, key, context)
+	if err != nil {
+		return "", err
+	}
+
+	if !slices.Contains(allowed, value) {
+		return "", InvalidEnvError{
+			Key:    key,
+			Value:  value,
+			Reason: "must be one of: " + strings.Join(allowed, ", "),
+		}
+	}
+
+	return value, nil
+}
+
+// parseBool parses a boolean, accepting the strconv.ParseBool forms as well
+// as the common "yes"/"no" and "on"/"off" spellings.
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "on":
+		return true, nil
+	case "no", "off":
+		return false, nil
+	}
+
+	return strconv.ParseBool(value)
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInvalidEnvError_Error(t *testing.T) {
+	err := InvalidEnvError{Key: "APP_PORT", Value: "abc", Reason: "must be an integer"}
+	expected := `config: env "APP_PORT" has invalid value "abc": must be an integer`
+	if got := err.Error(); got != expected {
+		t.Errorf("InvalidEnvError.Error() = %q, want %q", got, expected)
+	}
+}
+
+// typedResult classifies the error returned by a typed Require* helper.
+func typedResult(err error) string {
+	var missingErr MissingEnvError
+	var invalidErr InvalidEnvError
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.As(err, &missingErr):
+		return "missing"
+	case errors.As(err, &invalidErr):
+		return "invalid"
+	default:
+		return "other"
+	}
+}
+
+func TestRequireTyped(t *testing.T) {
+	const key = "TEST_REQUIRE_TYPED"
+
+	tests := []struct {
+		name     string
+		envValue string
+		call     func() (any, error)
+		want     any
+		wantKind string
+	}{
+		{"int ok", "42", func() (any, error) { return RequireInt(key, "ctx") }, 42, "ok"},
+		{"int missing", "", func() (any, error) { return RequireInt(key, "ctx") }, 0, "missing"},
+		{"int invalid", "4x2", func() (any, error) { return RequireInt(key, "ctx") }, 0, "invalid"},
+		{"int in range", "5", func() (any, error) { return RequireIntInRange(key, "ctx", 1, 10) }, 5, "ok"},
+		{"int out of range", "11", func() (any, error) { return RequireIntInRange(key, "ctx", 1, 10) }, 0, "invalid"},
+		{"port ok", "8080", func() (any, error) { return RequirePort(key, "ctx") }, 8080, "ok"},
+		{"port zero", "0", func() (any, error) { return RequirePort(key, "ctx") }, 0, "invalid"},
+		{"port too large", "70000", func() (any, error) { return RequirePort(key, "ctx") }, 0, "invalid"},
+		{"bool true", "true", func() (any, error) { return RequireBool(key, "ctx") }, true, "ok"},
+		{"bool yes", "YES", func() (any, error) { return RequireBool(key, "ctx") }, true, "ok"},
+		{"bool off", "off", func() (any, error) { return RequireBool(key, "ctx") }, false, "ok"},
+		{"bool invalid", "maybe", func() (any, error) { return RequireBool(key, "ctx") }, false, "invalid"},
+		{"duration ok", "1m30s", func() (any, error) { return RequireDuration(key, "ctx") }, 90 * time.Second, "ok"},
+		{"duration invalid", "soon", func() (any, error) { return RequireDuration(key, "ctx") }, time.Duration(0), "invalid"},
+		{"duration in range", "5s", func() (any, error) {
+			return RequireDurationInRange(key, "ctx", time.Second, time.Minute)
+		}, 5 * time.Second, "ok"},
+		{"duration out of range", "2m", func() (any, error) {
+			return RequireDurationInRange(key, "ctx", time.Second, time.Minute)
+		}, time.Duration(0), "invalid"},
+		{"enum ok", "debug", func() (any, error) { return RequireEnum(key, "ctx", "debug", "info") }, "debug", "ok"},
+		{"enum invalid", "trace", func() (any, error) { return RequireEnum(key, "ctx", "debug", "info") }, "", "invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(key, tt.envValue)
+
+			got, err := tt.call()
+			if kind := typedResult(err); kind != tt.wantKind {
+				t.Fatalf("error kind = %q, want %q (err: %v)", kind, tt.wantKind, err)
+			}
+			if got != tt.want {
+				t.Errorf("value = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireInRange_RawValue(t *testing.T) {
+	const key = "TEST_REQUIRE_RAW"
+
+	tests := []struct {
+		envValue string
+		call     func() error
+	}{
+		{" +11 ", func() error { _, err := RequireIntInRange(key, "ctx", 1, 10); return err }},
+		{"90m", func() error { _, err := RequireDurationInRange(key, "ctx", time.Second, time.Hour); return err }},
+	}
+
+	for _, tt := range tests {
+		t.Setenv(key, tt.envValue)
+
+		var invalidErr InvalidEnvError
+		if err := tt.call(); !errors.As(err, &invalidErr) || invalidErr.Value != strings.TrimSpace(tt.envValue) {
+			t.Errorf("error for %q = %#v, want the raw value", tt.envValue, err)
+		}
+	}
+}
+
+func TestRequireURL(t *testing.T) {
+	const key = "TEST_REQUIRE_URL"
+
+	t.Run("absolute url", func(t *testing.T) {
+		t.Setenv(key, "https://example.com/path")
+		got, err := RequireURL(key, "ctx")
+		if err != nil {
+			t.Fatalf("RequireURL() unexpected error: %v", err)
+		}
+		if got.Host != "example.com" || got.Path != "/path" {
+			t.Errorf("RequireURL() = %v", got)
+		}
+	})
+
+	t.Run("relative url", func(t *testing.T) {
+		t.Setenv(key, "/just/a/path")
+		got, err := RequireURL(key, "ctx")
+		if typedResult(err) != "invalid" || got != nil {
+			t.Errorf("RequireURL() = %v, %v; want nil, InvalidEnvError", got, err)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		t.Setenv(key, "")
+		if _, err := RequireURL(key, "ctx"); typedResult(err) != "missing" {
+			t.Errorf("RequireURL() error = %v, want MissingEnvError", err)
+		}
+	})
+}
+
+func TestLoadAccumulator_TypedMust(t *testing.T) {
+	t.Setenv("TEST_MUST_PORT", "8080")
+	t.Setenv("TEST_MUST_DEBUG", "nope")
+	t.Setenv("TEST_MUST_TIMEOUT", "10s")
+	t.Setenv("TEST_MUST_URL", "https://example.com")
+	t.Setenv("TEST_MUST_LEVEL", "info")
+	t.Setenv("TEST_MUST_WORKERS", "")
+
+	acc := &LoadAccumulator{}
+	port := acc.MustPort("TEST_MUST_PORT", "server")
+	acc.MustBool("TEST_MUST_DEBUG", "server")
+	timeout := acc.MustDuration("TEST_MUST_TIMEOUT", "server")
+	baseURL := acc.MustURL("TEST_MUST_URL", "server")
+	level := acc.MustEnum("TEST_MUST_LEVEL", "logging", "debug", "info")
+	acc.MustInt("TEST_MUST_WORKERS", "queue")
+
+	if port != 8080 || timeout != 10*time.Second || baseURL == nil || level != "info" {
+		t.Errorf("unexpected values: port=%d timeout=%v url=%v level=%q", port, timeout, baseURL, level)
+	}
+
+	var validationErr ValidationError
+	if !errors.As(acc.Err(), &validationErr) {
+		t.Fatalf("LoadAccumulator.Err() type = %T, want ValidationError", acc.Err())
+	}
+
+	errs := validationErr.Errors()
+	if len(errs) != 2 {
+		t.Fatalf("ValidationError has %d errors, want 2", len(errs))
+	}
+	if typedResult(errs[0]) != "invalid" || typedResult(errs[1]) != "missing" {
+		t.Errorf("unexpected error kinds: %v", errs)
+	}
+}
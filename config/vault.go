@@ -0,0 +1,289 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/dracory/envenc"
+)
+
+// Vault provides authoring operations on an EnvEnc vault file protected by
+// a public/private key pair, built on the envenc primitives.
+type Vault struct {
+	path       string
+	publicKey  string
+	privateKey string
+}
+
+// VaultDiff lists the keys that differ between two vaults. Values are not
+// included so the result is safe to print.
+type VaultDiff struct {
+	// Added holds keys present only in the second vault
+	Added []string `json:"added"`
+
+	// Removed holds keys present only in the first vault
+	Removed []string `json:"removed"`
+
+	// Changed holds keys present in both vaults with different values
+	Changed []string `json:"changed"`
+}
+
+// IsEmpty reports whether the two vaults hold identical keys and values.
+func (d VaultDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// NewVault creates a handle for the vault file at vaultFilePath. The file
+// is not touched until an operation is called.
+func NewVault(vaultFilePath, publicKey, privateKey string) *Vault {
+	return &Vault{
+		path:       vaultFilePath,
+		publicKey:  publicKey,
+		privateKey: privateKey,
+	}
+}
+
+// Path returns the vault file path
+func (v *Vault) Path() string {
+	return v.path
+}
+
+// Create initializes a new, empty vault file. It fails if the file exists.
+func (v *Vault) Create() error {
+	if fileExists(v.path) {
+		return &EnvEncError{
+			Operation: "vault_exists",
+			Message:   "Vault file already exists: '" + v.path + "'",
+		}
+	}
+
+	derivedKey, err := v.derivedKey()
+	if err != nil {
+		return err
+	}
+
+	if err := envenc.Init(v.path, derivedKey); err != nil {
+		return &EnvEncError{
+			Operation: "create_vault",
+			Message:   "Failed to create vault file '" + v.path + "': " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// Set stores value under key, replacing any existing value.
+func (v *Vault) Set(key, value string) error {
+	if err := validateVaultKey(key); err != nil {
+		return err
+	}
+
+	derivedKey, err := v.openKey()
+	if err != nil {
+		return err
+	}
+
+	if err := envenc.KeySet(v.path, derivedKey, key, value); err != nil {
+		return &EnvEncError{
+			Operation: "set_key",
+			Message:   "Failed to set key '" + key + "': " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// Unset removes key from the vault. Removing a missing key is not an error.
+func (v *Vault) Unset(key string) error {
+	if err := validateVaultKey(key); err != nil {
+		return err
+	}
+
+	derivedKey, err := v.openKey()
+	if err != nil {
+		return err
+	}
+
+	if err := envenc.KeyRemove(v.path, derivedKey, key); err != nil {
+		return &EnvEncError{
+			Operation: "unset_key",
+			Message:   "Failed to unset key '" + key + "': " + err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// List returns all variables stored in the vault.
+func (v *Vault) List() (map[string]string, error) {
+	derivedKey, err := v.openKey()
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := vaultKeyList(v.path, derivedKey)
+	if err != nil {
+		return nil, &EnvEncError{
+			Operation: "read_vault",
+			Message:   "Failed to read vault file '" + v.path + "': " + err.Error(),
+		}
+	}
+
+	return keys, nil
+}
+
+// Keys returns the names of all variables stored in the vault, sorted.
+func (v *Vault) Keys() ([]string, error) {
+	values, err := v.List()
+	if err != nil {
+		return nil, err
+	}
+
+	return sortedKeys(values), nil
+}
+
+// Rotate re-encrypts the vault with a new public/private key pair. The new
+// vault is written next to the original and then renamed over it, so the
+// original is left untouched if any step fails.
+func (v *Vault) Rotate(newPublicKey, newPrivateKey string) error {
+	values, err := v.List()
+	if err != nil {
+		return err
+	}
+
+	rotated := NewVault(v.path+".rotate", newPublicKey, newPrivateKey)
+	if err := os.Remove(rotated.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return &EnvEncError{
+			Operation: "rotate_vault",
+			Message:   "Failed to remove stale file '" + rotated.path + "': " + err.Error(),
+		}
+	}
+
+	if err := rotated.Create(); err != nil {
+		return err
+	}
+
+	for _, key := range sortedKeys(values) {
+		if err := rotated.Set(key, values[key]); err != nil {
+			os.Remove(rotated.path)
+			return err
+		}
+	}
+
+	if err := os.Rename(rotated.path, v.path); err != nil {
+		os.Remove(rotated.path)
+		return &EnvEncError{
+			Operation: "rotate_vault",
+			Message:   "Failed to replace vault file '" + v.path + "': " + err.Error(),
+		}
+	}
+
+	v.publicKey = newPublicKey
+	v.privateKey = newPrivateKey
+
+	return nil
+}
+
+// DiffVaults compares the variables of two vaults, which may use different
+// key pairs.
+func DiffVaults(a, b *Vault) (VaultDiff, error) {
+	aValues, err := a.List()
+	if err != nil {
+		return VaultDiff{}, err
+	}
+
+	bValues, err := b.List()
+	if err != nil {
+		return VaultDiff{}, err
+	}
+
+	diff := VaultDiff{}
+	for _, key := range sortedKeys(aValues) {
+		bValue, found := bValues[key]
+		switch {
+		case !found:
+			diff.Removed = append(diff.Removed, key)
+		case bValue != aValues[key]:
+			diff.Changed = append(diff.Changed, key)
+		}
+	}
+	for _, key := range sortedKeys(bValues) {
+		if _, found := aValues[key]; !found {
+			diff.Added = append(diff.Added, key)
+		}
+	}
+
+	return diff, nil
+}
+
+// derivedKey derives the vault password from the key pair.
+func (v *Vault) derivedKey() (string, error) {
+	derivedKey, err := envenc.DeriveKey(v.publicKey, v.privateKey)
+	if err != nil {
+		return "", &EnvEncError{
+			Operation: "derive_key",
+			Message:   "Failed to derive encryption key: " + err.Error(),
+		}
+	}
+	return derivedKey, nil
+}
+
+// openKey checks that the vault file exists and derives its password.
+func (v *Vault) openKey() (string, error) {
+	if !fileExists(v.path) {
+		return "", &EnvEncError{
+			Operation: "vault_not_found",
+			Message:   "Vault file not found: '" + v.path + "'",
+		}
+	}
+	return v.derivedKey()
+}
+
+// validateVaultKey rejects blank keys and the envenc bookkeeping key.
+func validateVaultKey(key string) error {
+	if strings.TrimSpace(key) == "" {
+		return &EnvEncError{Operation: "invalid_key", Message: "Key cannot be empty"}
+	}
+	if key == vaultInternalKey {
+		return &EnvEncError{Operation: "invalid_key", Message: "Key '" + key + "' is reserved"}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of values in sorted order.
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// vaultInternalKey is the bookkeeping key envenc's underlying data object
+// stores alongside the user's variables.
+const vaultInternalKey = "id"
+
+// vaultKeyList returns the variables stored in the vault file, excluding the
+// internal bookkeeping key.
+func vaultKeyList(vaultFilePath, derivedKey string) (map[string]string, error) {
+	keys, err := envenc.KeyListFromFile(vaultFilePath, derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	delete(keys, vaultInternalKey)
+	return keys, nil
+}
+
+// vaultKeyListFromString returns the variables stored in the vault content,
+// excluding the internal bookkeeping key.
+func vaultKeyListFromString(vaultContent, derivedKey string) (map[string]string, error) {
+	keys, err := envenc.KeyListFromString(vaultContent, derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	delete(keys, vaultInternalKey)
+	return keys, nil
+}
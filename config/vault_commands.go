@@ -0,0 +1,258 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/dracory/base/cli"
+)
+
+// Environment keys holding the vault key pairs used by the vault commands.
+const (
+	envKeyPublic     = "ENV_ENCRYPTION_KEY_PUBLIC"
+	envKeyPrivate    = "ENV_ENCRYPTION_KEY_PRIVATE"
+	envKeyPublicNew  = "ENV_ENCRYPTION_KEY_PUBLIC_NEW"
+	envKeyPrivateNew = "ENV_ENCRYPTION_KEY_PRIVATE_NEW"
+)
+
+// RegisterVaultCommands registers vault authoring commands on dispatcher,
+// writing their output to w. The key pair is read from the
+// ENV_ENCRYPTION_KEY_PUBLIC and ENV_ENCRYPTION_KEY_PRIVATE environment
+// variables so secrets never appear in the command line. For the same reason
+// vault:set reads the value from stdin, or from the environment variable
+// named KEY when the value argument is "-".
+//
+// Commands:
+//   - vault:create <vault-file>
+//   - vault:set <vault-file> <KEY> [-]
+//   - vault:unset <vault-file> <KEY>
+//   - vault:list <vault-file> [--values]
+//   - vault:diff <vault-file-a> <vault-file-b>
+//   - vault:rotate <vault-file> (new pair from ENV_ENCRYPTION_KEY_PUBLIC_NEW
+//     and ENV_ENCRYPTION_KEY_PRIVATE_NEW)
+//
+// Returns:
+//   - error: If any of the command names is already registered
+func RegisterVaultCommands[T any](dispatcher *cli.Dispatcher[T], w io.Writer) error {
+	commands := []struct {
+		name        string
+		description string
+		handler     cli.CommandHandler[T]
+	}{
+		{"vault:create", "Create an empty EnvEnc vault file", vaultCreateCommand[T](w)},
+		{"vault:set", "Set a key in an EnvEnc vault file", vaultSetCommand[T](w)},
+		{"vault:unset", "Remove a key from an EnvEnc vault file", vaultUnsetCommand[T](w)},
+		{"vault:list", "List the keys of an EnvEnc vault file", vaultListCommand[T](w)},
+		{"vault:diff", "Show the keys that differ between two EnvEnc vault files", vaultDiffCommand[T](w)},
+		{"vault:rotate", "Re-encrypt an EnvEnc vault file with a new key pair", vaultRotateCommand[T](w)},
+	}
+
+	for _, command := range commands {
+		if err := dispatcher.RegisterCommand(command.name, command.description, command.handler); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func vaultCreateCommand[T any](w io.Writer) cli.CommandHandler[T] {
+	return func(_ T, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("usage: vault:create <vault-file>")
+		}
+
+		vault, err := vaultFromEnv(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := vault.Create(); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "Created vault %s\n", vault.Path())
+		return nil
+	}
+}
+
+func vaultSetCommand[T any](w io.Writer) cli.CommandHandler[T] {
+	return func(_ T, args []string) error {
+		if len(args) < 2 || len(args) > 3 || (len(args) == 3 && args[2] != "-") {
+			return fmt.Errorf("usage: vault:set <vault-file> <KEY> [-] (value from stdin, or from $KEY with -)")
+		}
+
+		value, err := vaultSetValue(args[1], len(args) == 3)
+		if err != nil {
+			return err
+		}
+
+		vault, err := vaultFromEnv(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := vault.Set(args[1], value); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "Set %s in %s\n", args[1], vault.Path())
+		return nil
+	}
+}
+
+// vaultSetValue reads the value for vault:set from the environment variable
+// named key when fromEnv is set, otherwise from stdin without its trailing
+// newline
+func vaultSetValue(key string, fromEnv bool) (string, error) {
+	if fromEnv {
+		value, ok := os.LookupEnv(key)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", key)
+		}
+		return value, nil
+	}
+
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("reading value for %s from stdin: %w", key, err)
+	}
+
+	value := strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	if value == "" {
+		return "", fmt.Errorf("no value for %s on stdin", key)
+	}
+
+	return value, nil
+}
+
+func vaultUnsetCommand[T any](w io.Writer) cli.CommandHandler[T] {
+	return func(_ T, args []string) error {
+		if len(args) != 2 {
+			return fmt.Errorf("usage: vault:unset <vault-file> <KEY>")
+		}
+
+		vault, err := vaultFromEnv(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := vault.Unset(args[1]); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "Removed %s from %s\n", args[1], vault.Path())
+		return nil
+	}
+}
+
+func vaultListCommand[T any](w io.Writer) cli.CommandHandler[T] {
+	return func(_ T, args []string) error {
+		showValues := slices.Contains(args, "--values")
+		args = slices.DeleteFunc(slices.Clone(args), func(arg string) bool { return arg == "--values" })
+		if len(args) != 1 {
+			return fmt.Errorf("usage: vault:list <vault-file> [--values]")
+		}
+
+		vault, err := vaultFromEnv(args[0])
+		if err != nil {
+			return err
+		}
+
+		values, err := vault.List()
+		if err != nil {
+			return err
+		}
+
+		for _, key := range sortedKeys(values) {
+			if showValues {
+				fmt.Fprintf(w, "%s=%s\n", key, values[key])
+			} else {
+				fmt.Fprintln(w, key)
+			}
+		}
+		return nil
+	}
+}
+
+func vaultDiffCommand[T any](w io.Writer) cli.CommandHandler[T] {
+	return func(_ T, args []string) error {
+		if len(args) != 2 {
+			return fmt.Errorf("usage: vault:diff <vault-file-a> <vault-file-b>")
+		}
+
+		a, err := vaultFromEnv(args[0])
+		if err != nil {
+			return err
+		}
+
+		b, err := vaultFromEnv(args[1])
+		if err != nil {
+			return err
+		}
+
+		diff, err := DiffVaults(a, b)
+		if err != nil {
+			return err
+		}
+
+		if diff.IsEmpty() {
+			fmt.Fprintln(w, "Vaults are identical")
+			return nil
+		}
+
+		for _, key := range diff.Added {
+			fmt.Fprintf(w, "+ %s\n", key)
+		}
+		for _, key := range diff.Removed {
+			fmt.Fprintf(w, "- %s\n", key)
+		}
+		for _, key := range diff.Changed {
+			fmt.Fprintf(w, "~ %s\n", key)
+		}
+		return nil
+	}
+}
+
+func vaultRotateCommand[T any](w io.Writer) cli.CommandHandler[T] {
+	return func(_ T, args []string) error {
+		if len(args) != 1 {
+			return fmt.Errorf("usage: vault:rotate <vault-file>")
+		}
+
+		vault, err := vaultFromEnv(args[0])
+		if err != nil {
+			return err
+		}
+
+		acc := &LoadAccumulator{}
+		newPublicKey := acc.MustString(envKeyPublicNew, "required to rotate the vault")
+		newPrivateKey := acc.MustString(envKeyPrivateNew, "required to rotate the vault")
+		if err := acc.Err(); err != nil {
+			return err
+		}
+
+		if err := vault.Rotate(newPublicKey, newPrivateKey); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(w, "Rotated vault %s\n", vault.Path())
+		return nil
+	}
+}
+
+// vaultFromEnv creates a Vault for path using the key pair from the
+// environment.
+func vaultFromEnv(path string) (*Vault, error) {
+	acc := &LoadAccumulator{}
+	publicKey := acc.MustString(envKeyPublic, "required to open the vault")
+	privateKey := acc.MustString(envKeyPrivate, "required to open the vault")
+	if err := acc.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewVault(path, publicKey, privateKey), nil
+}
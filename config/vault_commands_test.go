@@ -0,0 +1,148 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dracory/base/cli"
+)
+
+func TestRegisterVaultCommands(t *testing.T) {
+	publicKey := testPublicKey(t, "test")
+	t.Setenv(envKeyPublic, publicKey)
+	t.Setenv(envKeyPrivate, testVaultPrivateKey)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env.test.vault")
+	otherPath := filepath.Join(dir, ".env.other.vault")
+
+	var out bytes.Buffer
+	dispatcher := cli.NewDispatcher[any]()
+	if err := RegisterVaultCommands(dispatcher, &out); err != nil {
+		t.Fatalf("RegisterVaultCommands() unexpected error: %v", err)
+	}
+	if err := RegisterVaultCommands(dispatcher, &out); err == nil {
+		t.Error("RegisterVaultCommands() twice should fail")
+	}
+
+	run := func(args ...string) string {
+		t.Helper()
+		out.Reset()
+		if err := dispatcher.ExecuteCommand(nil, args); err != nil {
+			t.Fatalf("%v unexpected error: %v", args, err)
+		}
+		return out.String()
+	}
+
+	run("vault:create", path)
+	setStdin(t, "localhost\n")
+	run("vault:set", path, "DB_HOST")
+	t.Setenv("DB_PASSWORD", "secret")
+	run("vault:set", path, "DB_PASSWORD", "-")
+	setStdin(t, "x")
+	run("vault:set", path, "TEMP")
+	run("vault:unset", path, "TEMP")
+
+	if got := run("vault:list", path); got != "DB_HOST\nDB_PASSWORD\n" {
+		t.Errorf("vault:list output = %q", got)
+	}
+	if got := run("vault:list", path, "--values"); got != "DB_HOST=localhost\nDB_PASSWORD=secret\n" {
+		t.Errorf("vault:list --values output = %q", got)
+	}
+
+	run("vault:create", otherPath)
+	setStdin(t, "db.internal\n")
+	run("vault:set", otherPath, "DB_HOST")
+	t.Setenv("API_KEY", "abc")
+	run("vault:set", otherPath, "API_KEY", "-")
+
+	diff := run("vault:diff", path, otherPath)
+	for _, want := range []string{"+ API_KEY", "- DB_PASSWORD", "~ DB_HOST"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("vault:diff output missing %q:\n%s", want, diff)
+		}
+	}
+	if strings.Contains(diff, "secret") || strings.Contains(diff, "localhost") {
+		t.Errorf("vault:diff output leaked values:\n%s", diff)
+	}
+	if got := run("vault:diff", path, path); !strings.Contains(got, "identical") {
+		t.Errorf("vault:diff of same vault = %q", got)
+	}
+
+	newPublicKey := testPublicKey(t, "rotated")
+	newPrivateKey := "rotated-private-key-0123456789abcdefghijklmnop"
+	t.Setenv(envKeyPublicNew, newPublicKey)
+	t.Setenv(envKeyPrivateNew, newPrivateKey)
+	run("vault:rotate", path)
+
+	values, err := NewVault(path, newPublicKey, newPrivateKey).List()
+	if err != nil || values["DB_PASSWORD"] != "secret" {
+		t.Errorf("rotated vault = %v, %v", values, err)
+	}
+}
+
+func TestRegisterVaultCommands_Errors(t *testing.T) {
+	var out bytes.Buffer
+	dispatcher := cli.NewDispatcher[any]()
+	if err := RegisterVaultCommands(dispatcher, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(envKeyPublic, "")
+	t.Setenv(envKeyPrivate, "")
+
+	err := dispatcher.ExecuteCommand(nil, []string{"vault:list", "some.vault"})
+	var validationErr ValidationError
+	if !errors.As(err, &validationErr) || len(validationErr.Errors()) != 2 {
+		t.Errorf("vault:list without keys error = %v, want ValidationError with 2 errors", err)
+	}
+
+	for _, args := range [][]string{
+		{"vault:create"},
+		{"vault:set", "some.vault"},
+		{"vault:set", "some.vault", "KEY", "secret"},
+		{"vault:unset", "some.vault"},
+		{"vault:list"},
+		{"vault:diff", "a.vault"},
+		{"vault:rotate"},
+	} {
+		err := dispatcher.ExecuteCommand(nil, args)
+		if err == nil || !strings.HasPrefix(err.Error(), "usage:") {
+			t.Errorf("%v error = %v, want usage error", args, err)
+		}
+	}
+
+	if err := dispatcher.ExecuteCommand(nil, []string{"vault:set", "some.vault", "VAULT_TEST_MISSING", "-"}); err == nil {
+		t.Error("vault:set from an unset environment variable should fail")
+	}
+	setStdin(t, "")
+	if err := dispatcher.ExecuteCommand(nil, []string{"vault:set", "some.vault", "KEY"}); err == nil {
+		t.Error("vault:set with an empty stdin should fail")
+	}
+}
+
+// setStdin replaces os.Stdin with a file holding content for the rest of
+// the test
+func setStdin(t *testing.T, content string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "stdin")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdin := os.Stdin
+	os.Stdin = file
+	t.Cleanup(func() {
+		os.Stdin = stdin
+		file.Close()
+	})
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dracory/envenc"
+)
+
+// testPublicKey returns an obfuscated public key usable with envenc.DeriveKey.
+func testPublicKey(t *testing.T, seed string) string {
+	t.Helper()
+	publicKey, err := envenc.Obfuscate(seed + "-public-key-0123456789abcdefghijklmnop")
+	if err != nil {
+		t.Fatalf("Obfuscate() error: %v", err)
+	}
+	return publicKey
+}
+
+func TestVault_Lifecycle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env.test.vault")
+	vault := NewVault(path, testPublicKey(t, "test"), testVaultPrivateKey)
+
+	if vault.Path() != path {
+		t.Errorf("Path() = %q, want %q", vault.Path(), path)
+	}
+
+	if err := vault.Set("KEY", "value"); err == nil {
+		t.Error("Set() on missing vault should fail")
+	}
+
+	if err := vault.Create(); err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+	if err := vault.Create(); err == nil {
+		t.Error("Create() on existing vault should fail")
+	}
+
+	keys, err := vault.Keys()
+	if err != nil || len(keys) != 0 {
+		t.Fatalf("Keys() on new vault = %v, %v; want empty", keys, err)
+	}
+
+	for k, v := range map[string]string{"DB_HOST": "localhost", "DB_PASSWORD": "secret", "APP_NAME": "demo"} {
+		if err := vault.Set(k, v); err != nil {
+			t.Fatalf("Set(%q) unexpected error: %v", k, err)
+		}
+	}
+	if err := vault.Set("DB_HOST", "db.internal"); err != nil {
+		t.Fatalf("Set() overwrite unexpected error: %v", err)
+	}
+	if err := vault.Unset("APP_NAME"); err != nil {
+		t.Fatalf("Unset() unexpected error: %v", err)
+	}
+
+	values, err := vault.List()
+	if err != nil {
+		t.Fatalf("List() unexpected error: %v", err)
+	}
+	want := map[string]string{"DB_HOST": "db.internal", "DB_PASSWORD": "secret"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("List() = %v, want %v", values, want)
+	}
+
+	keys, _ = vault.Keys()
+	if !reflect.DeepEqual(keys, []string{"DB_HOST", "DB_PASSWORD"}) {
+		t.Errorf("Keys() = %v", keys)
+	}
+}
+
+func TestVault_InvalidKeys(t *testing.T) {
+	path, publicKey := createTestVault(t, t.TempDir(), ".env.test.vault", nil)
+	vault := NewVault(path, publicKey, testVaultPrivateKey)
+
+	for _, key := range []string{"", "  ", "id"} {
+		var envEncErr *EnvEncError
+		if err := vault.Set(key, "value"); !errors.As(err, &envEncErr) || envEncErr.Operation != "invalid_key" {
+			t.Errorf("Set(%q) error = %v, want invalid_key", key, err)
+		}
+	}
+}
+
+func TestVault_Rotate(t *testing.T) {
+	path, oldPublicKey := createTestVault(t, t.TempDir(), ".env.test.vault", map[string]string{
+		"DB_HOST":     "localhost",
+		"DB_PASSWORD": "secret",
+	})
+
+	newPublicKey := testPublicKey(t, "rotated")
+	newPrivateKey := "rotated-private-key-0123456789abcdefghijklmnop"
+
+	vault := NewVault(path, oldPublicKey, testVaultPrivateKey)
+	if err := vault.Rotate(newPublicKey, newPrivateKey); err != nil {
+		t.Fatalf("Rotate() unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(path + ".rotate"); !errors.Is(err, os.ErrNotExist) {
+		t.Error("Rotate() left the temporary file behind")
+	}
+
+	if _, err := NewVault(path, oldPublicKey, testVaultPrivateKey).List(); err == nil {
+		t.Error("old key pair should no longer open the vault")
+	}
+
+	values, err := NewVault(path, newPublicKey, newPrivateKey).List()
+	if err != nil {
+		t.Fatalf("List() with new key pair unexpected error: %v", err)
+	}
+	if values["DB_HOST"] != "localhost" || values["DB_PASSWORD"] != "secret" || len(values) != 2 {
+		t.Errorf("rotated vault values = %v", values)
+	}
+
+	if _, err := vault.List(); err != nil {
+		t.Errorf("Rotate() should update the handle's key pair: %v", err)
+	}
+}
+
+func TestVault_RotateKeepsOriginalOnFailure(t *testing.T) {
+	path, publicKey := createTestVault(t, t.TempDir(), ".env.test.vault", map[string]string{"KEY": "value"})
+	vault := NewVault(path, publicKey, testVaultPrivateKey)
+
+	if err := vault.Rotate("short", "short"); err == nil {
+		t.Fatal("Rotate() with invalid key pair should fail")
+	}
+
+	values, err := vault.List()
+	if err != nil || values["KEY"] != "value" {
+		t.Errorf("original vault should be intact: %v, %v", values, err)
+	}
+}
+
+func TestDiffVaults(t *testing.T) {
+	dir := t.TempDir()
+	aPath, publicKey := createTestVault(t, dir, "a.vault", map[string]string{
+		"SAME":    "1",
+		"CHANGED": "old",
+		"REMOVED": "x",
+	})
+	bPath, _ := createTestVault(t, dir, "b.vault", map[string]string{
+		"SAME":    "1",
+		"CHANGED": "new",
+		"ADDED":   "y",
+	})
+
+	a := NewVault(aPath, publicKey, testVaultPrivateKey)
+	b := NewVault(bPath, publicKey, testVaultPrivateKey)
+
+	diff, err := DiffVaults(a, b)
+	if err != nil {
+		t.Fatalf("DiffVaults() unexpected error: %v", err)
+	}
+
+	want := VaultDiff{Added: []string{"ADDED"}, Removed: []string{"REMOVED"}, Changed: []string{"CHANGED"}}
+	if !reflect.DeepEqual(diff, want) {
+		t.Errorf("DiffVaults() = %+v, want %+v", diff, want)
+	}
+
+	same, err := DiffVaults(a, a)
+	if err != nil || !same.IsEmpty() {
+		t.Errorf("DiffVaults(a, a) = %+v, %v; want empty", same, err)
+	}
+}
@@ -0,0 +1,334 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultWatchInterval is the polling interval used when
+// WatcherOptions.Interval is not set.
+const DefaultWatchInterval = 5 * time.Second
+
+// ChangeEvent describes a key whose value changed during a reload. Old is
+// nil for added keys and New is nil for removed keys.
+type ChangeEvent struct {
+	Key string
+	Old any
+	New any
+}
+
+// WatcherOptions configures a Watcher.
+type WatcherOptions struct {
+	// Sources are loaded with LoadLayered on every reload, e.g. a JSON file
+	// source followed by a vault file source
+	Sources []SourceInterface
+
+	// Files are polled for modification time and size changes, a reload only
+	// happens when one of them changed. When empty every tick reloads
+	Files []string
+
+	// Interval is the polling interval (defaults to DefaultWatchInterval)
+	Interval time.Duration
+
+	// Validate checks a freshly loaded configuration, recording problems in
+	// acc. The Must* helpers of acc read the values of cfg, not the process
+	// environment. A configuration with errors is rejected and the previous
+	// one kept
+	Validate func(cfg ConfigInterface, acc *LoadAccumulator)
+
+	// OnError is called with reload errors while polling, optional
+	OnError func(err error)
+}
+
+// Watcher keeps a configuration up to date by polling its sources, so long
+// running services pick up changed values without a restart.
+//
+// Reloads are all-or-nothing: a new configuration is only swapped in when all
+// sources load and validation passes, and subscribers are notified after the
+// swap.
+type Watcher struct {
+	options WatcherOptions
+	state   atomic.Pointer[watchState]
+
+	reloadMu    sync.Mutex
+	fingerprint string
+
+	subscribersMu sync.RWMutex
+	subscribers   map[int]watchSubscriber
+	nextID        int
+}
+
+// watchState is an immutable snapshot of a loaded configuration.
+type watchState struct {
+	config     ConfigInterface
+	provenance Provenance
+	values     map[string]any
+}
+
+// watchSubscriber is a callback registered for a key or key prefix.
+type watchSubscriber struct {
+	key      string
+	isPrefix bool
+	callback func(ChangeEvent)
+}
+
+// matches reports whether the subscriber is interested in key.
+func (s watchSubscriber) matches(key string) bool {
+	if s.isPrefix {
+		return strings.HasPrefix(key, s.key)
+	}
+	return s.key == key
+}
+
+// NewWatcher creates a watcher and performs the initial load.
+//
+// Example:
+//
+//	watcher, err := config.NewWatcher(config.WatcherOptions{
+//		Sources: []config.SourceInterface{config.NewJSONFileSource("config.json")},
+//		Files:   []string{"config.json"},
+//	})
+//	watcher.SubscribePrefix("FEATURE_", func(event config.ChangeEvent) {
+//		log.Printf("%s changed from %v to %v", event.Key, event.Old, event.New)
+//	})
+//	go watcher.Run(ctx)
+//
+// Returns:
+//   - *Watcher: the watcher holding the initial configuration
+//   - error: If no sources are given, or the initial load or validation fails
+func NewWatcher(options WatcherOptions) (*Watcher, error) {
+	if len(options.Sources) == 0 {
+		return nil, errors.New("config: watcher requires at least one source")
+	}
+	if options.Interval <= 0 {
+		options.Interval = DefaultWatchInterval
+	}
+
+	w := &Watcher{
+		options:     options,
+		subscribers: map[int]watchSubscriber{},
+	}
+
+	w.fingerprint = w.currentFingerprint()
+
+	state, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	w.state.Store(state)
+
+	return w, nil
+}
+
+// Config returns the current configuration. The returned value is replaced,
+// not modified, by later reloads.
+func (w *Watcher) Config() ConfigInterface {
+	return w.state.Load().config
+}
+
+// Provenance returns the source of each key in the current configuration.
+func (w *Watcher) Provenance() Provenance {
+	return w.state.Load().provenance
+}
+
+// Get returns the current value of key.
+func (w *Watcher) Get(key string) any {
+	return w.state.Load().values[key]
+}
+
+// Subscribe registers callback for changes of key. Callbacks run after the
+// reload lock is released, so they may call Reload or Poll.
+//
+// Returns:
+//   - func(): unsubscribes the callback
+func (w *Watcher) Subscribe(key string, callback func(ChangeEvent)) func() {
+	return w.subscribe(watchSubscriber{key: key, callback: callback})
+}
+
+// SubscribePrefix registers callback for changes of every key starting with
+// prefix; an empty prefix matches all keys.
+//
+// Returns:
+//   - func(): unsubscribes the callback
+func (w *Watcher) SubscribePrefix(prefix string, callback func(ChangeEvent)) func() {
+	return w.subscribe(watchSubscriber{key: prefix, isPrefix: true, callback: callback})
+}
+
+// subscribe stores subscriber and returns its unsubscribe function.
+func (w *Watcher) subscribe(subscriber watchSubscriber) func() {
+	w.subscribersMu.Lock()
+	defer w.subscribersMu.Unlock()
+
+	id := w.nextID
+	w.nextID++
+	w.subscribers[id] = subscriber
+
+	return func() {
+		w.subscribersMu.Lock()
+		defer w.subscribersMu.Unlock()
+		delete(w.subscribers, id)
+	}
+}
+
+// Reload loads the sources, validates the result and, when valid, swaps it
+// in and notifies subscribers. On error the current configuration is kept.
+//
+// Returns:
+//   - []ChangeEvent: the changed keys in sorted order
+//   - error: If loading or validation fails
+func (w *Watcher) Reload() ([]ChangeEvent, error) {
+	w.reloadMu.Lock()
+	events, err := w.reload(w.currentFingerprint())
+	w.reloadMu.Unlock()
+
+	w.notify(events)
+	return events, err
+}
+
+// reload loads and swaps in a new state, recording fingerprint as the state
+// of the watched files only when it succeeds, so a failed reload is retried
+// by the next Poll. The caller must hold reloadMu and notify the returned
+// events after releasing it.
+func (w *Watcher) reload(fingerprint string) ([]ChangeEvent, error) {
+	next, err := w.load()
+	if err != nil {
+		return nil, err
+	}
+	w.fingerprint = fingerprint
+
+	previous := w.state.Swap(next)
+	return diffValues(previous.values, next.values), nil
+}
+
+// Poll reloads when one of the watched files changed since the last
+// successful load, or unconditionally when no files are watched. After a
+// failed reload the files still count as changed, so the next Poll retries.
+func (w *Watcher) Poll() ([]ChangeEvent, error) {
+	w.reloadMu.Lock()
+	fingerprint := w.currentFingerprint()
+	if len(w.options.Files) > 0 && fingerprint == w.fingerprint {
+		w.reloadMu.Unlock()
+		return nil, nil
+	}
+	events, err := w.reload(fingerprint)
+	w.reloadMu.Unlock()
+
+	w.notify(events)
+	return events, err
+}
+
+// Run polls every interval until ctx is done. Errors are passed to
+// WatcherOptions.OnError.
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.Poll(); err != nil && w.options.OnError != nil {
+				w.options.OnError(err)
+			}
+		}
+	}
+}
+
+// load builds and validates a new state from the sources.
+func (w *Watcher) load() (*watchState, error) {
+	cfg, provenance, err := LoadLayered(w.options.Sources...)
+	if err != nil {
+		return nil, err
+	}
+
+	if w.options.Validate != nil {
+		acc := NewLookupAccumulator(ConfigLookup(cfg))
+		w.options.Validate(cfg, acc)
+		if err := acc.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	values := make(map[string]any, len(provenance))
+	for _, key := range provenance.Keys() {
+		values[key] = cfg.Get(key)
+	}
+
+	return &watchState{config: cfg, provenance: provenance, values: values}, nil
+}
+
+// notify calls the subscribers matching each event.
+func (w *Watcher) notify(events []ChangeEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	w.subscribersMu.RLock()
+	ids := make([]int, 0, len(w.subscribers))
+	for id := range w.subscribers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	subscribers := make([]watchSubscriber, 0, len(ids))
+	for _, id := range ids {
+		subscribers = append(subscribers, w.subscribers[id])
+	}
+	w.subscribersMu.RUnlock()
+
+	for _, event := range events {
+		for _, subscriber := range subscribers {
+			if subscriber.matches(event.Key) {
+				subscriber.callback(event)
+			}
+		}
+	}
+}
+
+// currentFingerprint summarizes the modification time and size of the
+// watched files; missing files are included so their creation is noticed.
+func (w *Watcher) currentFingerprint() string {
+	var builder strings.Builder
+	for _, path := range w.options.Files {
+		builder.WriteString(path)
+		if info, err := os.Stat(path); err == nil {
+			builder.WriteString("|" + info.ModTime().String())
+			builder.WriteString("|" + strconv.FormatInt(info.Size(), 10))
+		}
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+// diffValues returns the changes between before and after in key order.
+func diffValues(before, after map[string]any) []ChangeEvent {
+	keys := map[string]bool{}
+	for key := range before {
+		keys[key] = true
+	}
+	for key := range after {
+		keys[key] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	events := []ChangeEvent{}
+	for _, key := range sorted {
+		if !reflect.DeepEqual(before[key], after[key]) {
+			events = append(events, ChangeEvent{Key: key, Old: before[key], New: after[key]})
+		}
+	}
+	return events
+}
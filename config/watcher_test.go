@@ -0,0 +1,263 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func writeWatchedFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcher_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	start := time.Now().Add(-time.Hour)
+	writeWatchedFile(t, path, `{"FEATURE_SEARCH": true, "FEATURE_CHAT": false, "APP_NAME": "demo"}`, start)
+
+	watcher, err := NewWatcher(WatcherOptions{
+		Sources: []SourceInterface{NewJSONFileSource(path)},
+		Files:   []string{path},
+		Validate: func(cfg ConfigInterface, acc *LoadAccumulator) {
+			if cfg.Get("APP_NAME") == nil {
+				acc.Add(MissingEnvError{Key: "APP_NAME", Context: "required by watcher test"})
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewWatcher() unexpected error: %v", err)
+	}
+
+	var features, names []ChangeEvent
+	watcher.SubscribePrefix("FEATURE_", func(event ChangeEvent) { features = append(features, event) })
+	unsubscribe := watcher.Subscribe("APP_NAME", func(event ChangeEvent) { names = append(names, event) })
+
+	if events, err := watcher.Poll(); err != nil || events != nil {
+		t.Fatalf("Poll() without file change = %v, %v; want no reload", events, err)
+	}
+
+	initial := watcher.Config()
+	writeWatchedFile(t, path, `{"FEATURE_SEARCH": true, "FEATURE_CHAT": true, "FEATURE_NEW": 1, "APP_NAME": "renamed"}`, start.Add(time.Minute))
+
+	events, err := watcher.Poll()
+	if err != nil {
+		t.Fatalf("Poll() unexpected error: %v", err)
+	}
+	if len(events) != 3 {
+		t.Errorf("Poll() events = %v, want 3", events)
+	}
+
+	wantFeatures := []ChangeEvent{
+		{Key: "FEATURE_CHAT", Old: false, New: true},
+		{Key: "FEATURE_NEW", Old: nil, New: float64(1)},
+	}
+	if !reflect.DeepEqual(features, wantFeatures) {
+		t.Errorf("prefix subscriber got %v, want %v", features, wantFeatures)
+	}
+	if len(names) != 1 || names[0].Old != "demo" || names[0].New != "renamed" {
+		t.Errorf("key subscriber got %v", names)
+	}
+	if initial.Get("APP_NAME") != "demo" || watcher.Get("APP_NAME") != "renamed" {
+		t.Error("reload must swap in a new configuration without modifying the previous one")
+	}
+
+	t.Run("invalid configuration is rejected", func(t *testing.T) {
+		unsubscribe()
+		writeWatchedFile(t, path, `{"FEATURE_CHAT": false}`, start.Add(2*time.Minute))
+
+		if _, err := watcher.Poll(); err == nil {
+			t.Fatal("Poll() expected validation error")
+		}
+		if watcher.Get("FEATURE_CHAT") != true || watcher.Get("APP_NAME") != "renamed" {
+			t.Error("invalid reload must keep the previous configuration")
+		}
+		if len(features) != 2 {
+			t.Errorf("subscribers must not be notified on failed reloads: %v", features)
+		}
+	})
+
+	t.Run("broken file is rejected", func(t *testing.T) {
+		writeWatchedFile(t, path, `{`, start.Add(3*time.Minute))
+		if _, err := watcher.Reload(); err == nil {
+			t.Fatal("Reload() expected parse error")
+		}
+		if watcher.Get("APP_NAME") != "renamed" {
+			t.Error("failed reload must keep the previous configuration")
+		}
+	})
+}
+
+func TestWatcher_SubscriberMayReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	start := time.Now().Add(-time.Hour)
+	writeWatchedFile(t, path, `{"APP_NAME": "demo"}`, start)
+
+	watcher, err := NewWatcher(WatcherOptions{
+		Sources: []SourceInterface{NewJSONFileSource(path)},
+		Files:   []string{path},
+	})
+	if err != nil {
+		t.Fatalf("NewWatcher() unexpected error: %v", err)
+	}
+
+	calls := 0
+	watcher.Subscribe("APP_NAME", func(ChangeEvent) {
+		calls++
+		if _, err := watcher.Reload(); err != nil {
+			t.Errorf("Reload() from a subscriber unexpected error: %v", err)
+		}
+	})
+
+	writeWatchedFile(t, path, `{"APP_NAME": "renamed"}`, start.Add(time.Minute))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watcher.Poll()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Poll() deadlocked on a subscriber calling Reload")
+	}
+	if calls != 1 {
+		t.Errorf("subscriber called %d times, want 1", calls)
+	}
+}
+
+func TestWatcher_PollRetriesFailedReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	start := time.Now().Add(-time.Hour)
+	writeWatchedFile(t, path, `{"A": "one"}`, start)
+
+	var mu sync.Mutex
+	var remoteErr error
+	remote := NewSource("remote", func() (map[string]any, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return map[string]any{}, remoteErr
+	})
+
+	watcher, err := NewWatcher(WatcherOptions{
+		Sources: []SourceInterface{NewJSONFileSource(path), remote},
+		Files:   []string{path},
+	})
+	if err != nil {
+		t.Fatalf("NewWatcher() unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	remoteErr = os.ErrDeadlineExceeded
+	mu.Unlock()
+	writeWatchedFile(t, path, `{"A": "two"}`, start.Add(time.Minute))
+
+	if _, err := watcher.Poll(); err == nil {
+		t.Fatal("Poll() expected error while the remote source is down")
+	}
+
+	mu.Lock()
+	remoteErr = nil
+	mu.Unlock()
+
+	events, err := watcher.Poll()
+	if err != nil {
+		t.Fatalf("Poll() unexpected error: %v", err)
+	}
+	if len(events) != 1 || watcher.Get("A") != "two" {
+		t.Errorf("Poll() after recovery = %v, A = %v; want the changed file reloaded", events, watcher.Get("A"))
+	}
+
+	if events, err := watcher.Poll(); err != nil || events != nil {
+		t.Errorf("Poll() after a successful reload = %v, %v; want no reload", events, err)
+	}
+}
+
+func TestWatcher_ValidateReadsConfig(t *testing.T) {
+	t.Setenv("APP_PORT", "8080")
+
+	var mu sync.Mutex
+	port := "9000"
+	source := NewSource("memory", func() (map[string]any, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return map[string]any{"APP_PORT": port}, nil
+	})
+
+	watcher, err := NewWatcher(WatcherOptions{
+		Sources: []SourceInterface{source},
+		Validate: func(cfg ConfigInterface, acc *LoadAccumulator) {
+			acc.MustPort("APP_PORT", "required by watcher test")
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewWatcher() unexpected error: %v", err)
+	}
+
+	mu.Lock()
+	port = "99999"
+	mu.Unlock()
+
+	if _, err := watcher.Reload(); err == nil {
+		t.Fatal("Reload() expected validation error for the reloaded port, not the environment one")
+	}
+	if watcher.Get("APP_PORT") != "9000" {
+		t.Errorf("APP_PORT = %v, want the previous value", watcher.Get("APP_PORT"))
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+	var mu sync.Mutex
+	value := "one"
+	source := NewSource("memory", func() (map[string]any, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return map[string]any{"KEY": value}, nil
+	})
+
+	watcher, err := NewWatcher(WatcherOptions{Sources: []SourceInterface{source}, Interval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewWatcher() unexpected error: %v", err)
+	}
+
+	changed := make(chan ChangeEvent, 1)
+	watcher.Subscribe("KEY", func(event ChangeEvent) { changed <- event })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go watcher.Run(ctx)
+
+	mu.Lock()
+	value = "two"
+	mu.Unlock()
+
+	select {
+	case event := <-changed:
+		if event.Old != "one" || event.New != "two" {
+			t.Errorf("event = %+v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("watcher did not pick up the change")
+	}
+}
+
+func TestNewWatcher_Errors(t *testing.T) {
+	if _, err := NewWatcher(WatcherOptions{}); err == nil {
+		t.Error("NewWatcher() without sources should fail")
+	}
+
+	missing := NewJSONFileSource(filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := NewWatcher(WatcherOptions{Sources: []SourceInterface{missing}}); err == nil {
+		t.Error("NewWatcher() should fail when the initial load fails")
+	}
+}
@@ -0,0 +1,256 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime"
+	"strings"
+)
+
+// Kind classifies an AppError.
+type Kind int
+
+// Error kinds. The zero value is KindInternal, so unclassified errors are
+// never exposed to clients.
+const (
+	KindInternal Kind = iota
+	KindNotFound
+	KindInvalid
+	KindUnauthorized
+	KindForbidden
+	KindConflict
+)
+
+// String returns the name of the kind, e.g. "not_found".
+func (k Kind) String() string {
+	switch k {
+	case KindNotFound:
+		return "not_found"
+	case KindInvalid:
+		return "invalid"
+	case KindUnauthorized:
+		return "unauthorized"
+	case KindForbidden:
+		return "forbidden"
+	case KindConflict:
+		return "conflict"
+	default:
+		return "internal"
+	}
+}
+
+// HTTPStatus returns the HTTP status code of the kind.
+func (k Kind) HTTPStatus() int {
+	switch k {
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindInvalid:
+		return http.StatusUnprocessableEntity
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindConflict:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// AppError is a classified application error. Message is safe to show to
+// users, while Err, the cause, and the stack are kept for logs.
+type AppError struct {
+	Kind    Kind
+	Message string
+	Err     error
+
+	stack []uintptr
+}
+
+// NewAppError creates an AppError of kind with a public message.
+func NewAppError(kind Kind, message string) *AppError {
+	return &AppError{Kind: kind, Message: message}
+}
+
+// NotFound creates a KindNotFound error with a public message.
+func NotFound(message string) *AppError {
+	return NewAppError(KindNotFound, message)
+}
+
+// Invalid creates a KindInvalid error with a public message.
+func Invalid(message string) *AppError {
+	return NewAppError(KindInvalid, message)
+}
+
+// Unauthorized creates a KindUnauthorized error with a public message.
+func Unauthorized(message string) *AppError {
+	return NewAppError(KindUnauthorized, message)
+}
+
+// Forbidden creates a KindForbidden error with a public message.
+func Forbidden(message string) *AppError {
+	return NewAppError(KindForbidden, message)
+}
+
+// Conflict creates a KindConflict error with a public message.
+func Conflict(message string) *AppError {
+	return NewAppError(KindConflict, message)
+}
+
+// Internal creates a KindInternal error with a public message.
+func Internal(message string) *AppError {
+	return NewAppError(KindInternal, message)
+}
+
+// Wrap classifies err with kind and a public message, keeping err as the
+// private cause. It returns an untyped nil when err is nil, so it can be
+// returned directly from functions returning error.
+func Wrap(err error, kind Kind, message string) error {
+	if err == nil {
+		return nil
+	}
+	return &AppError{Kind: kind, Message: message, Err: err}
+}
+
+// WithCause sets the private cause and returns e.
+func (e *AppError) WithCause(err error) *AppError {
+	e.Err = err
+	return e
+}
+
+// WithStack records the stack of the caller and returns e.
+func (e *AppError) WithStack() *AppError {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	e.stack = pcs[:n]
+	return e
+}
+
+// Error returns the kind, the public message and the cause, for logs.
+func (e *AppError) Error() string {
+	parts := []string{e.Kind.String()}
+	if e.Message != "" {
+		parts = append(parts, e.Message)
+	}
+	if e.Err != nil {
+		parts = append(parts, e.Err.Error())
+	}
+	return strings.Join(parts, ": ")
+}
+
+// Unwrap returns the cause.
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// StackTrace returns the recorded stack, one "function\n\tfile:line" entry
+// per frame, or an empty string when WithStack was not called.
+func (e *AppError) StackTrace() string {
+	if len(e.stack) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	frames := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := frames.Next()
+		fmt.Fprintf(&builder, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		if !more {
+			break
+		}
+	}
+	return builder.String()
+}
+
+// LogValue groups the error details for slog: kind, status, message, cause
+// and stack when present.
+func (e *AppError) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("kind", e.Kind.String()),
+		slog.Int("status", e.Kind.HTTPStatus()),
+		slog.String("message", e.Message),
+	}
+	if e.Err != nil {
+		attrs = append(attrs, slog.String("cause", e.Err.Error()))
+	}
+	if stack := e.StackTrace(); stack != "" {
+		attrs = append(attrs, slog.String("stack", stack))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// AsAppError returns the first *AppError in err's chain.
+func AsAppError(err error) (*AppError, bool) {
+	var appErr *AppError
+	if errors.As(err, &appErr) && appErr != nil {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// KindOf returns the kind of the first *AppError in err's chain, or
+// KindInternal for unclassified errors.
+func KindOf(err error) Kind {
+	if appErr, ok := AsAppError(err); ok {
+		return appErr.Kind
+	}
+	return KindInternal
+}
+
+// IsKind reports whether err is classified as kind.
+func IsKind(err error, kind Kind) bool {
+	return err != nil && KindOf(err) == kind
+}
+
+// IsNotFound reports whether err is classified as KindNotFound.
+func IsNotFound(err error) bool {
+	return IsKind(err, KindNotFound)
+}
+
+// HTTPStatus returns the HTTP status code for err: http.StatusOK for nil,
+// the kind's status for an *AppError and 500 otherwise.
+func HTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	return KindOf(err).HTTPStatus()
+}
+
+// PublicMessage returns the message safe to show to users: the message of
+// the *AppError in err's chain, or the HTTP status text when it has none or
+// err is not classified. Causes are never included.
+func PublicMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	if appErr, ok := AsAppError(err); ok && appErr.Message != "" {
+		return appErr.Message
+	}
+	return http.StatusText(HTTPStatus(err))
+}
+
+// Log writes err to logger with msg: internal errors at error level,
+// classified client errors at warning level. Details of an *AppError are
+// logged under the "error" group.
+func Log(ctx context.Context, logger *slog.Logger, msg string, err error) {
+	if err == nil {
+		return
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	level := slog.LevelWarn
+	if KindOf(err) == KindInternal {
+		level = slog.LevelError
+	}
+
+	if appErr, ok := AsAppError(err); ok {
+		logger.LogAttrs(ctx, level, msg, slog.Any("error", appErr))
+		return
+	}
+	logger.LogAttrs(ctx, level, msg, slog.String("error", err.Error()))
+}
@@ -0,0 +1,136 @@
+package errs
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func Test_AppErrorWrap(t *testing.T) {
+	err := fmt.Errorf("loading user: %w", Wrap(sql.ErrNoRows, KindNotFound, "user not found"))
+
+	if !IsNotFound(err) {
+		t.Fatal(`error should be not found, but found:`, KindOf(err))
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatal(`cause should be reachable with errors.Is`)
+	}
+
+	if HTTPStatus(err) != http.StatusNotFound {
+		t.Fatal(`status should be 404, but found:`, HTTPStatus(err))
+	}
+
+	if PublicMessage(err) != "user not found" {
+		t.Fatal(`public message should be "user not found", but found:`, PublicMessage(err))
+	}
+
+	if !strings.Contains(err.Error(), "not_found: user not found: sql: no rows") {
+		t.Fatal(`error should contain kind, message and cause, but found:`, err.Error())
+	}
+
+	if Wrap(nil, KindInternal, "boom") != nil {
+		t.Fatal(`wrapping nil should return nil`)
+	}
+}
+
+func Test_AppErrorWrapNil(t *testing.T) {
+	find := func() error {
+		var err error
+		return Wrap(err, KindNotFound, "user not found")
+	}
+
+	err := find()
+	if err != nil {
+		t.Fatal(`wrapping nil should return a nil error, but found:`, err)
+	}
+
+	if HTTPStatus(err) != http.StatusOK || PublicMessage(err) != "" {
+		t.Fatal(`nil error should map to 200 without a message, but found:`, HTTPStatus(err), PublicMessage(err))
+	}
+
+	var typedNil *AppError
+	if KindOf(typedNil) != KindInternal {
+		t.Fatal(`typed nil should be internal, but found:`, KindOf(typedNil))
+	}
+}
+
+func Test_AppErrorStatus(t *testing.T) {
+	cases := map[Kind]int{
+		KindNotFound:     http.StatusNotFound,
+		KindInvalid:      http.StatusUnprocessableEntity,
+		KindUnauthorized: http.StatusUnauthorized,
+		KindForbidden:    http.StatusForbidden,
+		KindConflict:     http.StatusConflict,
+		KindInternal:     http.StatusInternalServerError,
+	}
+
+	for kind, status := range cases {
+		if HTTPStatus(NewAppError(kind, "")) != status {
+			t.Fatal(`unexpected status for`, kind, HTTPStatus(NewAppError(kind, "")))
+		}
+	}
+
+	if HTTPStatus(nil) != http.StatusOK {
+		t.Fatal(`status of nil should be 200, but found:`, HTTPStatus(nil))
+	}
+}
+
+func Test_AppErrorUnclassified(t *testing.T) {
+	err := errors.New("dial tcp: connection refused")
+
+	if KindOf(err) != KindInternal {
+		t.Fatal(`unclassified error should be internal, but found:`, KindOf(err))
+	}
+
+	if PublicMessage(err) != "Internal Server Error" {
+		t.Fatal(`public message should not leak the cause, but found:`, PublicMessage(err))
+	}
+
+	if PublicMessage(Forbidden("")) != "Forbidden" {
+		t.Fatal(`empty message should fall back to status text, but found:`, PublicMessage(Forbidden("")))
+	}
+}
+
+func Test_AppErrorStack(t *testing.T) {
+	if Internal("boom").StackTrace() != "" {
+		t.Fatal(`stack should be empty without WithStack`)
+	}
+
+	stack := Internal("boom").WithStack().StackTrace()
+	if !strings.Contains(stack, "Test_AppErrorStack") {
+		t.Fatal(`stack should contain the caller, but found:`, stack)
+	}
+}
+
+func Test_AppErrorLog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewTextHandler(buf, nil))
+
+	Log(context.Background(), logger, "request failed", Conflict("email taken").WithCause(errors.New("duplicate key")))
+	line := buf.String()
+
+	for _, want := range []string{"level=WARN", "error.kind=conflict", "error.status=409", `error.cause="duplicate key"`} {
+		if !strings.Contains(line, want) {
+			t.Fatal(`log should contain`, want, `but found:`, line)
+		}
+	}
+
+	buf.Reset()
+	Log(context.Background(), logger, "request failed", errors.New("disk full"))
+	if !strings.Contains(buf.String(), "level=ERROR") || !strings.Contains(buf.String(), `error="disk full"`) {
+		t.Fatal(`unclassified error should be logged at error level, but found:`, buf.String())
+	}
+
+	buf.Reset()
+	Log(context.Background(), logger, "request failed", nil)
+	if buf.Len() != 0 {
+		t.Fatal(`nil error should not be logged, but found:`, buf.String())
+	}
+}
@@ -0,0 +1,100 @@
+package errs
+
+import "fmt"
+
+// StepError reports which step of a Chain failed. Step counts from 1 and
+// Name is empty for steps added with Then.
+type StepError struct {
+	Step int
+	Name string
+	Err  error
+}
+
+// Error returns the failing step followed by the error message.
+func (e *StepError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("step %d: %v", e.Step, e.Err)
+	}
+	return fmt.Sprintf("step %d (%s): %v", e.Step, e.Name, e.Err)
+}
+
+// Unwrap returns the error of the failing step.
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// Chain threads a value through a sequence of steps, each receiving the
+// result of the previous one, e.g. string -> *url.URL -> []byte:
+//
+//	body, err := errs.Result(
+//		errs.ThenNamed(
+//			errs.ThenNamed(errs.Start(raw), "parse", url.Parse),
+//			"download", download,
+//		),
+//	)
+//
+// After a step fails the following steps are skipped and the error, a
+// *StepError naming the step, is carried to the end of the chain.
+type Chain[T any] struct {
+	value T
+	err   error
+	steps int
+}
+
+// Start begins a chain with value.
+func Start[T any](value T) Chain[T] {
+	return Chain[T]{value: value}
+}
+
+// StartWith begins a chain with the result of valueOrErrorFunc, which counts
+// as the first step.
+func StartWith[T any](valueOrErrorFunc valueOrErrorFunc[T]) Chain[T] {
+	return ThenNamed(Chain[struct{}]{}, "", func(struct{}) (T, error) {
+		return valueOrErrorFunc()
+	})
+}
+
+// Then adds a step receiving the current value and returning the next one,
+// possibly of another type.
+func Then[T, U any](chain Chain[T], step func(T) (U, error)) Chain[U] {
+	return ThenNamed(chain, "", step)
+}
+
+// ThenNamed adds a step like Then, naming it in the *StepError reported
+// when it fails.
+func ThenNamed[T, U any](chain Chain[T], name string, step func(T) (U, error)) Chain[U] {
+	next := Chain[U]{err: chain.err, steps: chain.steps + 1}
+	if chain.err != nil {
+		return next
+	}
+
+	value, err := step(chain.value)
+	if err != nil {
+		next.err = &StepError{Step: next.steps, Name: name, Err: err}
+		return next
+	}
+
+	next.value = value
+	return next
+}
+
+// Result returns the final value, or the zero value and the *StepError of
+// the failing step.
+func Result[T any](chain Chain[T]) (T, error) {
+	if chain.err != nil {
+		var zero T
+		return zero, chain.err
+	}
+	return chain.value, nil
+}
+
+// Value returns the final value, or the zero value when a step failed.
+func (c Chain[T]) Value() T {
+	value, _ := Result(c)
+	return value
+}
+
+// Err returns the *StepError of the failing step, or nil.
+func (c Chain[T]) Err() error {
+	return c.err
+}
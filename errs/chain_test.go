@@ -0,0 +1,92 @@
+package errs
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func Test_ChainSuccess(t *testing.T) {
+	chain := Start(" https://example.com/path ")
+	trimmed := Then(chain, func(s string) (string, error) {
+		return strings.TrimSpace(s), nil
+	})
+	parsed := ThenNamed(trimmed, "parse", url.Parse)
+	host := Then(parsed, func(u *url.URL) ([]byte, error) {
+		return []byte(u.Host), nil
+	})
+
+	value, err := Result(host)
+	if err != nil {
+		t.Fatal(`unexpected error`, err)
+	}
+
+	if string(value) != "example.com" {
+		t.Fatal(`value should be "example.com", but found:`, string(value))
+	}
+}
+
+func Test_ChainFail(t *testing.T) {
+	errNegative := errors.New("must not be negative")
+	calls := 0
+
+	parsed := ThenNamed(Start("-5"), "parse", strconv.Atoi)
+	checked := ThenNamed(parsed, "check", func(n int) (int, error) {
+		if n < 0 {
+			return 0, errNegative
+		}
+		return n, nil
+	})
+	doubled := Then(checked, func(n int) (string, error) {
+		calls++
+		return strconv.Itoa(n * 2), nil
+	})
+
+	value, err := Result(doubled)
+	if calls != 0 {
+		t.Fatal(`steps after the failing one must not run`)
+	}
+
+	if value != "" {
+		t.Fatal(`value must be the zero value, but found:`, value)
+	}
+
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Step != 2 || stepErr.Name != "check" {
+		t.Fatal(`unexpected step error:`, err)
+	}
+
+	if !errors.Is(err, errNegative) {
+		t.Fatal(`step error must unwrap to the step's error`)
+	}
+
+	if err.Error() != "step 2 (check): must not be negative" {
+		t.Fatal(`unexpected message:`, err.Error())
+	}
+
+	if doubled.Err() != err || doubled.Value() != "" {
+		t.Fatal(`Err and Value must match Result`)
+	}
+}
+
+func Test_ChainStartWith(t *testing.T) {
+	errLoad := errors.New("load failed")
+
+	_, err := Result(Then(StartWith(func() (string, error) {
+		return "", errLoad
+	}), strconv.Atoi))
+
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Step != 1 || err.Error() != "step 1: load failed" {
+		t.Fatal(`unexpected error:`, err)
+	}
+
+	value, err := Result(Then(StartWith(func() (string, error) {
+		return "42", nil
+	}), strconv.Atoi))
+	if err != nil || value != 42 {
+		t.Fatal(`unexpected result:`, value, err)
+	}
+}
@@ -0,0 +1,120 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+type ctxErrFunc func(ctx context.Context) error
+
+type ctxPipeline struct {
+	errFuncs []ctxErrFunc
+}
+
+func newCtxPipeline() *ctxPipeline {
+	return &ctxPipeline{
+		errFuncs: []ctxErrFunc{},
+	}
+}
+
+func (p *ctxPipeline) add(errFunc ctxErrFunc) {
+	p.errFuncs = append(p.errFuncs, errFunc)
+}
+
+// run runs the functions in order, stopping at the first error or when ctx
+// is done.
+func (p *ctxPipeline) run(ctx context.Context) error {
+	for _, errFunc := range p.errFuncs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := errFunc(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// runAll runs the functions in order, collecting every error, until ctx is
+// done.
+func (p *ctxPipeline) runAll(ctx context.Context) error {
+	errs := []error{}
+
+	for _, errFunc := range p.errFuncs {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := errFunc(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// runConcurrent runs the functions in parallel, at most limit at a time
+// (unlimited when limit < 1). With firstError set, the first error cancels
+// the context passed to the other functions and is returned; otherwise all
+// errors are returned in the order of the functions.
+func (p *ctxPipeline) runConcurrent(ctx context.Context, limit int, firstError bool) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if limit < 1 || limit > len(p.errFuncs) {
+		limit = len(p.errFuncs)
+	}
+
+	errs := make([]error, len(p.errFuncs))
+	semaphore := make(chan struct{}, limit)
+
+	var firstErr error
+	var once sync.Once
+	var wg sync.WaitGroup
+
+	for i, errFunc := range p.errFuncs {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			if !firstError {
+				errs[i] = ctx.Err()
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, errFunc ctxErrFunc) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			err := errFunc(ctx)
+			if err == nil {
+				return
+			}
+
+			errs[i] = err
+			if firstError {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(i, errFunc)
+	}
+
+	wg.Wait()
+
+	if firstError {
+		if firstErr != nil {
+			return firstErr
+		}
+		return context.Cause(ctx)
+	}
+
+	return errors.Join(errs...)
+}
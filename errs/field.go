@@ -0,0 +1,51 @@
+package errs
+
+// FieldError is an error attached to a named field, such as a form input.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+// Error returns the field name followed by the error message.
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Field attaches the field name to the error returned by errFunc, so the
+// result of Validate or ValidateAll can be shown next to the input.
+func Field(name string, errFunc func() error) func() error {
+	return func() error {
+		if err := errFunc(); err != nil {
+			return &FieldError{Field: name, Err: err}
+		}
+		return nil
+	}
+}
+
+// FieldErrorList returns every *FieldError in err, including those combined
+// with errors.Join or wrapped with fmt.Errorf("%w"), in order.
+func FieldErrorList(err error) []*FieldError {
+	if err == nil {
+		return nil
+	}
+
+	if fieldErr, ok := err.(*FieldError); ok {
+		return []*FieldError{fieldErr}
+	}
+
+	result := []*FieldError{}
+	switch wrapped := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, inner := range wrapped.Unwrap() {
+			result = append(result, FieldErrorList(inner)...)
+		}
+	case interface{ Unwrap() error }:
+		result = append(result, FieldErrorList(wrapped.Unwrap())...)
+	}
+	return result
+}
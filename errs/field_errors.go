@@ -0,0 +1,164 @@
+package errs
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/dracory/hb"
+)
+
+// FormField is the field under which FieldErrorsFrom stores errors that
+// are not attached to a field.
+const FormField = "_form"
+
+// FieldErrors maps field names to their error messages, for rendering next
+// to form inputs or returning from an API. It implements error.
+type FieldErrors map[string][]string
+
+// NewFieldErrors creates an empty FieldErrors.
+func NewFieldErrors() FieldErrors {
+	return FieldErrors{}
+}
+
+// FieldErrorsFrom converts err into FieldErrors. *FieldError values, and
+// FieldErrors, found in err (also when combined with errors.Join) keep their
+// field; other errors are stored under FormField.
+func FieldErrorsFrom(err error) FieldErrors {
+	result := NewFieldErrors()
+	result.merge(err)
+	return result
+}
+
+// ValidateFields runs every function, like ValidateAll, and returns the
+// errors as FieldErrors. Wrap functions with Field to name their field.
+func ValidateFields(errFuncs ...errFunc) FieldErrors {
+	return FieldErrorsFrom(ValidateAll(errFuncs...))
+}
+
+// merge adds the errors found in err.
+func (f FieldErrors) merge(err error) {
+	if err == nil {
+		return
+	}
+
+	switch e := err.(type) {
+	case FieldErrors:
+		for _, field := range e.Fields() {
+			for _, message := range e[field] {
+				f.Add(field, message)
+			}
+		}
+	case *FieldError:
+		f.Add(e.Field, e.Err.Error())
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			f.merge(inner)
+		}
+	default:
+		var fieldErrors FieldErrors
+		if errors.As(err, &fieldErrors) {
+			f.merge(fieldErrors)
+			return
+		}
+		var fieldErr *FieldError
+		if errors.As(err, &fieldErr) {
+			f.Add(fieldErr.Field, fieldErr.Err.Error())
+			return
+		}
+		f.Add(FormField, err.Error())
+	}
+}
+
+// Add appends message to the messages of field.
+func (f FieldErrors) Add(field, message string) {
+	f[field] = append(f[field], message)
+}
+
+// Has reports whether field has any message.
+func (f FieldErrors) Has(field string) bool {
+	return len(f[field]) > 0
+}
+
+// Get returns the messages of field.
+func (f FieldErrors) Get(field string) []string {
+	return f[field]
+}
+
+// First returns the first message of field, or an empty string.
+func (f FieldErrors) First(field string) string {
+	if !f.Has(field) {
+		return ""
+	}
+	return f[field][0]
+}
+
+// Fields returns the fields with messages in sorted order.
+func (f FieldErrors) Fields() []string {
+	fields := make([]string, 0, len(f))
+	for field, messages := range f {
+		if len(messages) > 0 {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// IsEmpty reports whether there are no messages.
+func (f FieldErrors) IsEmpty() bool {
+	return len(f.Fields()) == 0
+}
+
+// Err returns f as an error, or nil when it is empty. Use it instead of
+// returning f directly, which would be a non-nil error even when empty.
+func (f FieldErrors) Err() error {
+	if f.IsEmpty() {
+		return nil
+	}
+	return f
+}
+
+// Error lists the messages as "field: message", sorted by field.
+func (f FieldErrors) Error() string {
+	lines := []string{}
+	for _, field := range f.Fields() {
+		for _, message := range f[field] {
+			lines = append(lines, field+": "+message)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// MarshalJSON writes a JSON object mapping each field with messages to an
+// array of messages, with fields in sorted order, e.g.
+// {"email":["is required"],"name":["is too short"]}. Empty FieldErrors
+// are written as {}.
+func (f FieldErrors) MarshalJSON() ([]byte, error) {
+	stable := make(map[string][]string, len(f))
+	for _, field := range f.Fields() {
+		stable[field] = f[field]
+	}
+	return json.Marshal(stable)
+}
+
+// InputClass returns class with Bootstrap's "is-invalid" appended when field
+// has messages, e.g. InputClass("email", "form-control").
+func (f FieldErrors) InputClass(field, class string) string {
+	if !f.Has(field) {
+		return class
+	}
+	return strings.TrimSpace(class + " is-invalid")
+}
+
+// InvalidFeedback renders the messages of field as Bootstrap
+// "invalid-feedback" markup, one div per message, or an empty tag when the
+// field has no messages. Messages are HTML escaped.
+func (f FieldErrors) InvalidFeedback(field string) *hb.Tag {
+	feedback := hb.Wrap()
+	for _, message := range f[field] {
+		feedback.Child(hb.Div().Class("invalid-feedback").Text(message))
+	}
+	return feedback
+}
@@ -0,0 +1,121 @@
+package errs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func Test_FieldErrors(t *testing.T) {
+	fields := NewFieldErrors()
+
+	if !fields.IsEmpty() || fields.Err() != nil {
+		t.Fatal(`new FieldErrors must be empty`)
+	}
+
+	fields.Add("name", "is too short")
+	fields.Add("email", "is required")
+	fields.Add("email", "must be an email address")
+
+	if !fields.Has("email") || fields.Has("age") {
+		t.Fatal(`unexpected Has results`)
+	}
+
+	if fields.First("email") != "is required" || fields.First("age") != "" {
+		t.Fatal(`unexpected First results`)
+	}
+
+	if !reflect.DeepEqual(fields.Fields(), []string{"email", "name"}) {
+		t.Fatal(`unexpected fields:`, fields.Fields())
+	}
+
+	if fields.Error() != "email: is required\nemail: must be an email address\nname: is too short" {
+		t.Fatal(`unexpected message:`, fields.Error())
+	}
+
+	var target FieldErrors
+	if !errors.As(fmt.Errorf("save: %w", fields.Err()), &target) || !target.Has("name") {
+		t.Fatal(`FieldErrors must be found with errors.As`)
+	}
+}
+
+func Test_FieldErrorsJSON(t *testing.T) {
+	fields := NewFieldErrors()
+	fields.Add("name", "is too short")
+	fields.Add("email", "is required")
+	fields["unused"] = nil
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatal(`unexpected error`, err)
+	}
+
+	if string(data) != `{"email":["is required"],"name":["is too short"]}` {
+		t.Fatal(`unexpected JSON:`, string(data))
+	}
+
+	data, _ = json.Marshal(NewFieldErrors())
+	if string(data) != `{}` {
+		t.Fatal(`empty FieldErrors must marshal to {}, but found:`, string(data))
+	}
+
+	decoded := NewFieldErrors()
+	if err := json.Unmarshal([]byte(`{"email":["is required"]}`), &decoded); err != nil || decoded.First("email") != "is required" {
+		t.Fatal(`FieldErrors must unmarshal from its JSON form:`, decoded, err)
+	}
+}
+
+func Test_FieldErrorsHTML(t *testing.T) {
+	fields := NewFieldErrors()
+	fields.Add("email", "is required")
+	fields.Add("email", "<b>not</b> an email")
+
+	html := fields.InvalidFeedback("email").ToHTML()
+	expected := `<div class="invalid-feedback">is required</div><div class="invalid-feedback">&lt;b&gt;not&lt;/b&gt; an email</div>`
+	if html != expected {
+		t.Fatal(`unexpected HTML:`, html)
+	}
+
+	if html := fields.InvalidFeedback("name").ToHTML(); html != "" {
+		t.Fatal(`field without errors must render nothing, but found:`, html)
+	}
+
+	if fields.InputClass("email", "form-control") != "form-control is-invalid" {
+		t.Fatal(`unexpected class:`, fields.InputClass("email", "form-control"))
+	}
+
+	if fields.InputClass("name", "form-control") != "form-control" {
+		t.Fatal(`unexpected class:`, fields.InputClass("name", "form-control"))
+	}
+}
+
+func Test_ValidateFields(t *testing.T) {
+	fields := ValidateFields(
+		Field("email", func() error { return errors.New("is required") }),
+		Field("name", func() error { return nil }),
+		func() error { return errors.New("form expired") },
+		Field("age", func() error { return fmt.Errorf("must be a number") }),
+	)
+
+	expected := FieldErrors{
+		"email":   {"is required"},
+		"age":     {"must be a number"},
+		FormField: {"form expired"},
+	}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Fatal(`unexpected field errors:`, fields)
+	}
+
+	if fields := ValidateFields(func() error { return nil }); fields.Err() != nil {
+		t.Fatal(`passing checks must produce no errors:`, fields)
+	}
+
+	nested := NewFieldErrors()
+	nested.Add("city", "is required")
+	merged := FieldErrorsFrom(errors.Join(nested, fmt.Errorf("wrapped: %w", &FieldError{Field: "zip", Err: errors.New("is invalid")})))
+	if merged.First("city") != "is required" || merged.First("zip") != "is invalid" {
+		t.Fatal(`unexpected merged field errors:`, merged)
+	}
+}
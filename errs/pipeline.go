@@ -1,5 +1,7 @@
 package errs
 
+import "errors"
+
 type errFunc func() error
 
 type pipeline struct {
@@ -28,3 +30,15 @@ func (p *pipeline) run() error {
 
 	return nil
 }
+
+func (p *pipeline) runAll() error {
+	errs := []error{}
+
+	for _, errFunc := range p.errFuncs {
+		if err := errFunc(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
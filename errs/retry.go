@@ -0,0 +1,185 @@
+package errs
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Retry defaults, used for the zero values of RetryOptions.
+const (
+	DefaultRetryAttempts     = 3
+	DefaultRetryInitialDelay = 100 * time.Millisecond
+	DefaultRetryMultiplier   = 2.0
+	DefaultRetryMaxDelay     = time.Hour
+)
+
+// RetryOptions configures Retry. Zero values fall back to the defaults.
+type RetryOptions struct {
+	// Attempts is the maximum number of calls, DefaultRetryAttempts if zero
+	Attempts int
+
+	// InitialDelay is the wait before the second attempt,
+	// DefaultRetryInitialDelay if zero
+	InitialDelay time.Duration
+
+	// MaxDelay caps the wait between attempts, DefaultRetryMaxDelay if zero
+	MaxDelay time.Duration
+
+	// Multiplier grows the wait after each attempt, DefaultRetryMultiplier
+	// if below 1
+	Multiplier float64
+
+	// Jitter randomizes each wait by up to this fraction in either
+	// direction, between 0 (none) and 1
+	Jitter float64
+
+	// MaxElapsed stops retrying when the next wait would end after this much
+	// time since the first attempt, unlimited if zero
+	MaxElapsed time.Duration
+
+	// Retryable decides which errors are retried, all errors if nil
+	Retryable func(err error) bool
+}
+
+// RetryError is returned by Retry when every attempt failed or retrying was
+// stopped. Attempts holds the error of each attempt in order, and Cause the
+// context error when the context ended the retries.
+type RetryError struct {
+	Attempts []error
+	Cause    error
+}
+
+// Error returns the number of attempts and the last error.
+func (e *RetryError) Error() string {
+	msg := fmt.Sprintf("errs: gave up after %d attempt(s)", len(e.Attempts))
+	if e.Cause != nil {
+		msg += ": " + e.Cause.Error()
+	}
+	if last := e.Last(); last != nil {
+		msg += ": " + last.Error()
+	}
+	return msg
+}
+
+// Last returns the error of the last attempt, or nil when none was made.
+func (e *RetryError) Last() error {
+	if len(e.Attempts) == 0 {
+		return nil
+	}
+	return e.Attempts[len(e.Attempts)-1]
+}
+
+// Unwrap returns the cause and the errors of all attempts, so errors.Is and
+// errors.As match any of them.
+func (e *RetryError) Unwrap() []error {
+	if e.Cause == nil {
+		return e.Attempts
+	}
+	return append([]error{e.Cause}, e.Attempts...)
+}
+
+// Retry calls fn until it succeeds, waiting with exponential backoff and
+// jitter between attempts. It stops when the attempts are used up, the
+// error is not retryable, the next wait would exceed MaxElapsed or ctx is
+// done.
+//
+// Returns:
+//   - error: nil on success, otherwise a *RetryError
+func Retry(ctx context.Context, opts RetryOptions, fn func(ctx context.Context) error) error {
+	_, err := RetryValue(ctx, opts, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// RetryValue is Retry for functions returning a value.
+//
+// Returns:
+//   - T: the value of the successful attempt, the zero value otherwise
+//   - error: nil on success, otherwise a *RetryError
+func RetryValue[T any](ctx context.Context, opts RetryOptions, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	opts = opts.withDefaults()
+	retryErr := &RetryError{Attempts: []error{}}
+	started := time.Now()
+	delay := opts.InitialDelay
+
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			retryErr.Cause = err
+			return zero, retryErr
+		}
+
+		value, err := fn(ctx)
+		if err == nil {
+			return value, nil
+		}
+		retryErr.Attempts = append(retryErr.Attempts, err)
+
+		if attempt >= opts.Attempts || (opts.Retryable != nil && !opts.Retryable(err)) {
+			return zero, retryErr
+		}
+
+		wait := opts.jittered(delay)
+		if opts.MaxElapsed > 0 && time.Since(started)+wait > opts.MaxElapsed {
+			return zero, retryErr
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			retryErr.Cause = ctx.Err()
+			return zero, retryErr
+		case <-timer.C:
+		}
+
+		delay = opts.next(delay)
+	}
+}
+
+// withDefaults fills in the zero values.
+func (o RetryOptions) withDefaults() RetryOptions {
+	if o.Attempts <= 0 {
+		o.Attempts = DefaultRetryAttempts
+	}
+	if o.InitialDelay <= 0 {
+		o.InitialDelay = DefaultRetryInitialDelay
+	}
+	if o.MaxDelay <= 0 {
+		o.MaxDelay = DefaultRetryMaxDelay
+	}
+	if o.InitialDelay > o.MaxDelay {
+		o.InitialDelay = o.MaxDelay
+	}
+	if o.Multiplier < 1 {
+		o.Multiplier = DefaultRetryMultiplier
+	}
+	o.Jitter = min(max(o.Jitter, 0), 1)
+	return o
+}
+
+// next grows delay by Multiplier, capped at MaxDelay. The product is
+// compared as a float so it cannot overflow time.Duration.
+func (o RetryOptions) next(delay time.Duration) time.Duration {
+	grown := float64(delay) * o.Multiplier
+	if grown >= float64(o.MaxDelay) {
+		return o.MaxDelay
+	}
+	return time.Duration(grown)
+}
+
+// jittered randomizes delay by up to Jitter in either direction.
+func (o RetryOptions) jittered(delay time.Duration) time.Duration {
+	if o.Jitter == 0 {
+		return delay
+	}
+	jittered := float64(delay) * (1 + o.Jitter*(2*rand.Float64()-1))
+	if jittered >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(jittered)
+}
@@ -0,0 +1,128 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func Test_RetrySuccess(t *testing.T) {
+	calls := 0
+	value, err := RetryValue(context.Background(), RetryOptions{Attempts: 5, InitialDelay: time.Millisecond, Jitter: 0.5}, func(ctx context.Context) (string, error) {
+		calls++
+		if calls < 3 {
+			return "", errors.New("temporary")
+		}
+		return "ok", nil
+	})
+
+	if err != nil {
+		t.Fatal(`unexpected error`, err)
+	}
+
+	if value != "ok" || calls != 3 {
+		t.Fatal(`should succeed on the third call, but found:`, value, calls)
+	}
+}
+
+func Test_RetryExhausted(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+	attempts := []error{errFirst, errSecond, errSecond}
+	calls := 0
+
+	err := Retry(context.Background(), RetryOptions{InitialDelay: time.Millisecond}, func(ctx context.Context) error {
+		calls++
+		return attempts[calls-1]
+	})
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatal(`error should be a *RetryError, but found:`, err)
+	}
+
+	if len(retryErr.Attempts) != DefaultRetryAttempts {
+		t.Fatal(`should record every attempt, but found:`, len(retryErr.Attempts))
+	}
+
+	if !errors.Is(err, errFirst) || retryErr.Last() != errSecond {
+		t.Fatal(`should match the attempt errors, but found:`, err)
+	}
+}
+
+func Test_RetryNotRetryable(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), RetryOptions{
+		Attempts:     5,
+		InitialDelay: time.Millisecond,
+		Retryable: func(err error) bool {
+			return KindOf(err) == KindInternal
+		},
+	}, func(ctx context.Context) error {
+		calls++
+		return NotFound("missing")
+	})
+
+	if calls != 1 || !IsNotFound(err) {
+		t.Fatal(`should stop at a non-retryable error, but found:`, calls, err)
+	}
+}
+
+func Test_RetryMaxElapsed(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), RetryOptions{
+		Attempts:     10,
+		InitialDelay: 20 * time.Millisecond,
+		MaxElapsed:   30 * time.Millisecond,
+	}, func(ctx context.Context) error {
+		calls++
+		return errors.New("temporary")
+	})
+
+	if err == nil || calls != 2 {
+		t.Fatal(`should stop before the wait exceeds MaxElapsed, but found:`, calls, err)
+	}
+}
+
+func Test_RetryContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+
+	err := Retry(ctx, RetryOptions{Attempts: 5, InitialDelay: time.Hour}, func(ctx context.Context) error {
+		calls++
+		cancel()
+		return errors.New("temporary")
+	})
+
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Fatal(`should stop when the context is canceled, but found:`, calls, err)
+	}
+}
+
+func Test_RetryDelayCapped(t *testing.T) {
+	tests := []RetryOptions{
+		{InitialDelay: time.Second, Multiplier: 10},
+		{InitialDelay: time.Second, Multiplier: 10, MaxDelay: time.Minute},
+		{InitialDelay: time.Second, Multiplier: 10, MaxDelay: math.MaxInt64, Jitter: 1},
+	}
+
+	for _, opts := range tests {
+		opts = opts.withDefaults()
+		delay := opts.InitialDelay
+		for range 100 {
+			delay = opts.next(delay)
+			if wait := opts.jittered(delay); delay <= 0 || delay > opts.MaxDelay || wait < 0 {
+				t.Fatal(`delay should stay positive and capped, but found:`, delay, wait, opts.MaxDelay)
+			}
+		}
+		if delay != opts.MaxDelay {
+			t.Fatal(`delay should reach MaxDelay, but found:`, delay)
+		}
+	}
+
+	if opts := (RetryOptions{}).withDefaults(); opts.MaxDelay != DefaultRetryMaxDelay {
+		t.Fatal(`MaxDelay should default to DefaultRetryMaxDelay, but found:`, opts.MaxDelay)
+	}
+}
@@ -29,3 +29,30 @@ func ValidateAndGet[T any](valueOrErrorFuncs ...valueOrErrorFunc[T]) (T, error)
 
 	return p.run()
 }
+
+// ValidateAll runs every function, unlike Validate which stops at the first
+// error, and returns the errors combined with errors.Join, or nil when all
+// pass. The combined error matches each of them with errors.Is and
+// errors.As. Wrap functions with Field to name the checked form field.
+func ValidateAll(errFuncs ...errFunc) error {
+	p := newPipeline()
+
+	for _, errFunc := range errFuncs {
+		p.add(errFunc)
+	}
+
+	return p.runAll()
+}
+
+// ValidateAndGetAll runs every function, unlike ValidateAndGet which stops
+// at the first error. It returns the value of the last successful function
+// and the errors combined with errors.Join.
+func ValidateAndGetAll[T any](valueOrErrorFuncs ...valueOrErrorFunc[T]) (T, error) {
+	p := newErrPipeline[T]()
+
+	for _, valueOrErrorFunc := range valueOrErrorFuncs {
+		p.add(valueOrErrorFunc)
+	}
+
+	return p.runAll()
+}
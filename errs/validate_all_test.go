@@ -0,0 +1,107 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func Test_ValidateAll(t *testing.T) {
+	errEmail := errors.New("email is required")
+	errAge := errors.New("age must be positive")
+	calls := 0
+
+	err := ValidateAll(func() error {
+		calls++
+		return errEmail
+	}, func() error {
+		calls++
+		return nil
+	}, func() error {
+		calls++
+		return errAge
+	})
+
+	if calls != 3 {
+		t.Fatal(`all functions must run, but ran:`, calls)
+	}
+
+	if !errors.Is(err, errEmail) || !errors.Is(err, errAge) {
+		t.Fatal(`combined error must match every error, but found:`, err)
+	}
+
+	if err.Error() != "email is required\nage must be positive" {
+		t.Fatal(`unexpected message:`, err.Error())
+	}
+
+	if err := ValidateAll(func() error { return nil }); err != nil {
+		t.Fatal(`unexpected error`, err)
+	}
+}
+
+func Test_ValidateAllFields(t *testing.T) {
+	errRequired := errors.New("is required")
+
+	err := ValidateAll(
+		Field("email", func() error { return errRequired }),
+		Field("name", func() error { return nil }),
+		Field("age", func() error { return fmt.Errorf("must be positive: %w", errRequired) }),
+	)
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) || fieldErr.Field != "email" {
+		t.Fatal(`errors.As must find the first field error, but found:`, fieldErr)
+	}
+
+	if !errors.Is(err, errRequired) {
+		t.Fatal(`field errors must unwrap to the original error`)
+	}
+
+	fields := FieldErrorList(fmt.Errorf("form: %w", err))
+	if len(fields) != 2 || fields[0].Field != "email" || fields[1].Field != "age" {
+		t.Fatal(`unexpected field errors:`, fields)
+	}
+
+	if fields[1].Error() != "age: must be positive: is required" {
+		t.Fatal(`unexpected message:`, fields[1].Error())
+	}
+
+	if FieldErrorList(nil) != nil {
+		t.Fatal(`nil error must have no field errors`)
+	}
+
+	// Validate stops at the first error but keeps the field name
+	err = Validate(
+		Field("email", func() error { return errRequired }),
+		Field("age", func() error { t.Fatal(`must not run`); return nil }),
+	)
+	if fields := FieldErrorList(err); len(fields) != 1 || fields[0].Field != "email" {
+		t.Fatal(`unexpected field errors:`, fields)
+	}
+}
+
+func Test_ValidateAndGetAll(t *testing.T) {
+	errFirst := errors.New("first")
+	errThird := errors.New("third")
+
+	value, err := ValidateAndGetAll(func() (int, error) {
+		return 0, errFirst
+	}, func() (int, error) {
+		return 2, nil
+	}, func() (int, error) {
+		return 0, errThird
+	})
+
+	if value != 2 {
+		t.Fatal(`value should be the last successful value 2, but found:`, value)
+	}
+
+	if !errors.Is(err, errFirst) || !errors.Is(err, errThird) {
+		t.Fatal(`combined error must match every error, but found:`, err)
+	}
+
+	value, err = ValidateAndGetAll(func() (int, error) { return 1, nil })
+	if err != nil || value != 1 {
+		t.Fatal(`unexpected result:`, value, err)
+	}
+}
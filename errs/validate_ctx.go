@@ -0,0 +1,54 @@
+package errs
+
+import "context"
+
+// ValidateCtx runs the functions in order like Validate, passing them ctx,
+// and stops with ctx.Err() as soon as ctx is done.
+func ValidateCtx(ctx context.Context, errFuncs ...ctxErrFunc) error {
+	p := newCtxPipeline()
+
+	for _, errFunc := range errFuncs {
+		p.add(errFunc)
+	}
+
+	return p.run(ctx)
+}
+
+// ValidateAllCtx runs the functions in order like ValidateAll, passing them
+// ctx. When ctx is done the remaining functions are skipped and ctx.Err() is
+// added to the combined error.
+func ValidateAllCtx(ctx context.Context, errFuncs ...ctxErrFunc) error {
+	p := newCtxPipeline()
+
+	for _, errFunc := range errFuncs {
+		p.add(errFunc)
+	}
+
+	return p.runAll(ctx)
+}
+
+// ValidateConcurrent runs independent functions in parallel, at most limit
+// at a time (no limit when limit < 1). The first error cancels the context
+// passed to the other functions and is returned.
+func ValidateConcurrent(ctx context.Context, limit int, errFuncs ...ctxErrFunc) error {
+	p := newCtxPipeline()
+
+	for _, errFunc := range errFuncs {
+		p.add(errFunc)
+	}
+
+	return p.runConcurrent(ctx, limit, true)
+}
+
+// ValidateAllConcurrent runs independent functions in parallel, at most
+// limit at a time (no limit when limit < 1), and returns every error
+// combined with errors.Join in the order of the functions.
+func ValidateAllConcurrent(ctx context.Context, limit int, errFuncs ...ctxErrFunc) error {
+	p := newCtxPipeline()
+
+	for _, errFunc := range errFuncs {
+		p.add(errFunc)
+	}
+
+	return p.runConcurrent(ctx, limit, false)
+}
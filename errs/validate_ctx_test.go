@@ -0,0 +1,146 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_ValidateCtx(t *testing.T) {
+	errFailed := errors.New("failed")
+	calls := 0
+
+	err := ValidateCtx(context.Background(), func(ctx context.Context) error {
+		calls++
+		return nil
+	}, func(ctx context.Context) error {
+		calls++
+		return errFailed
+	}, func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+
+	if !errors.Is(err, errFailed) || calls != 2 {
+		t.Fatal(`must stop at the first error, but found:`, err, calls)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	calls = 0
+	err = ValidateCtx(ctx, func(ctx context.Context) error {
+		calls++
+		cancel()
+		return nil
+	}, func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Fatal(`must stop when the context is done, but found:`, err, calls)
+	}
+}
+
+func Test_ValidateAllCtx(t *testing.T) {
+	errFirst := errors.New("first")
+	errSecond := errors.New("second")
+
+	err := ValidateAllCtx(context.Background(), func(ctx context.Context) error {
+		return errFirst
+	}, func(ctx context.Context) error {
+		return errSecond
+	})
+
+	if !errors.Is(err, errFirst) || !errors.Is(err, errSecond) {
+		t.Fatal(`must return every error, but found:`, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = ValidateAllCtx(ctx, func(ctx context.Context) error {
+		cancel()
+		return errFirst
+	}, func(ctx context.Context) error {
+		t.Fatal(`must not run after cancellation`)
+		return nil
+	})
+
+	if !errors.Is(err, errFirst) || !errors.Is(err, context.Canceled) {
+		t.Fatal(`must return the errors and the cancellation, but found:`, err)
+	}
+}
+
+func Test_ValidateConcurrent(t *testing.T) {
+	errFailed := errors.New("failed")
+	var cancelled atomic.Int32
+
+	slow := func(ctx context.Context) error {
+		select {
+		case <-ctx.Done():
+			cancelled.Add(1)
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return nil
+		}
+	}
+
+	start := time.Now()
+	err := ValidateConcurrent(context.Background(), 0, slow, slow, func(ctx context.Context) error {
+		return errFailed
+	})
+
+	if !errors.Is(err, errFailed) {
+		t.Fatal(`must return the first error, but found:`, err)
+	}
+
+	if cancelled.Load() != 2 || time.Since(start) > 2*time.Second {
+		t.Fatal(`the first error must cancel the other checks`)
+	}
+
+	if err := ValidateConcurrent(context.Background(), 2, func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatal(`unexpected error`, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := ValidateConcurrent(ctx, 1, func(ctx context.Context) error { return nil }); !errors.Is(err, context.Canceled) {
+		t.Fatal(`must return the context error, but found:`, err)
+	}
+}
+
+func Test_ValidateAllConcurrent(t *testing.T) {
+	errFirst := errors.New("first")
+	errThird := errors.New("third")
+
+	var running, maxRunning atomic.Int32
+	check := func(err error) ctxErrFunc {
+		return func(ctx context.Context) error {
+			current := running.Add(1)
+			defer running.Add(-1)
+			for {
+				seen := maxRunning.Load()
+				if current <= seen || maxRunning.CompareAndSwap(seen, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return err
+		}
+	}
+
+	err := ValidateAllConcurrent(context.Background(), 2,
+		check(errFirst), check(nil), check(errThird), check(nil), check(nil))
+
+	if !errors.Is(err, errFirst) || !errors.Is(err, errThird) {
+		t.Fatal(`must return every error, but found:`, err)
+	}
+
+	if err.Error() != "first\nthird" {
+		t.Fatal(`errors must keep the order of the checks, but found:`, err.Error())
+	}
+
+	if maxRunning.Load() > 2 {
+		t.Fatal(`concurrency limit exceeded:`, maxRunning.Load())
+	}
+}
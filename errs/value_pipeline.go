@@ -1,5 +1,7 @@
 package errs
 
+import "errors"
+
 type valueOrErrorFunc[T any] func() (T, error)
 
 type valuePipeline[T any] struct {
@@ -29,3 +31,19 @@ func (p *valuePipeline[T]) run() (T, error) {
 
 	return result, nil
 }
+
+func (p *valuePipeline[T]) runAll() (T, error) {
+	var result T
+	errs := []error{}
+
+	for _, errFunc := range p.errFuncs {
+		value, err := errFunc()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		result = value
+	}
+
+	return result, errors.Join(errs...)
+}
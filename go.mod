@@ -19,6 +19,7 @@ require (
 	github.com/dracory/userstore v1.6.0
 	github.com/dromara/carbon/v2 v2.6.16
 	github.com/google/uuid v1.6.0
+	github.com/joho/godotenv v1.5.1
 	github.com/jordan-wright/email v4.0.1-0.20210109023952-943e75fe5223+incompatible
 	github.com/pkg/errors v0.9.1
 	github.com/samber/lo v1.53.0
@@ -41,7 +42,6 @@ require (
 	github.com/dracory/uid v1.9.0 // indirect
 	github.com/dracory/websrv v0.1.0 // indirect
 	github.com/georgysavva/scany v1.2.3 // indirect
-	github.com/joho/godotenv v1.5.1 // indirect
 	github.com/logrusorgru/aurora v2.0.3+incompatible // indirect
 	github.com/mattn/go-colorable v0.1.14 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
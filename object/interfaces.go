@@ -1,5 +1,7 @@
 package object
 
+import "time"
+
 // PropertyObjectInterface defines the interface for objects
 // that can store and retrieve properties
 type PropertyObjectInterface interface {
@@ -33,3 +35,96 @@ type SerializablePropertyObjectInterface interface {
 	PropertyObjectInterface
 	SerializableInterface
 }
+
+// The interfaces below describe optional features of PropertyObject and
+// SerializablePropertyObject. They are kept out of the base interfaces so
+// that other implementations of those do not have to provide them; check for
+// a feature with a type assertion:
+//
+//	if paths, ok := obj.(object.PathAccessorInterface); ok {
+//		city := paths.GetPath("billing.address.city")
+//	}
+
+// PathAccessorInterface addresses nested maps and slices with paths such as
+// "billing.address.city" or "tags[2]"
+type PathAccessorInterface interface {
+	GetPath(path string) any
+	HasPath(path string) bool
+	SetPath(path string, value any) error
+	UnsetPath(path string)
+}
+
+// TypedGetterInterface reads properties as typed values. Each getter returns
+// defaultValue when the property is missing, nil or cannot be converted
+type TypedGetterInterface interface {
+	GetString(key string, defaultValue string) string
+	GetInt(key string, defaultValue int) int
+	GetInt64(key string, defaultValue int64) int64
+	GetFloat(key string, defaultValue float64) float64
+	GetBool(key string, defaultValue bool) bool
+	GetTime(key string, defaultValue time.Time) time.Time
+	GetSlice(key string, defaultValue []any) []any
+	GetMap(key string, defaultValue map[string]any) map[string]any
+}
+
+// ChangeTrackerInterface reports which properties changed since tracking
+// was enabled with EnableChangeTracking
+type ChangeTrackerInterface interface {
+	EnableChangeTracking()
+	IsDirty() bool
+	DirtyKeys() []string
+	OriginalValue(key string) (any, bool)
+	MarkClean()
+}
+
+// ObservableInterface calls observers after a property changes. Both
+// methods return a function that removes the observer
+type ObservableInterface interface {
+	OnChange(key string, callback func(oldValue, newValue any)) func()
+	OnAnyChange(callback func(key string, oldValue, newValue any)) func()
+}
+
+// HistoryInterface takes snapshots and keeps a bounded undo history
+type HistoryInterface interface {
+	Snapshot() Snapshot
+	Restore(snapshot Snapshot) error
+	Checkpoint()
+	Undo() bool
+	HistoryLen() int
+	SetHistoryLimit(limit int)
+}
+
+// SchemaValidatorInterface checks properties against a PropertySchema, on
+// Set and SetPath and with Validate
+type SchemaValidatorInterface interface {
+	SetSchema(schema *PropertySchema)
+	Schema() *PropertySchema
+	Validate() error
+}
+
+// NumberDecoderInterface deserializes JSON keeping numbers as json.Number,
+// so integers are not converted to float64
+type NumberDecoderInterface interface {
+	FromJSONWithNumbers(data []byte) error
+}
+
+// PatchableInterface applies JSON patches atomically
+type PatchableInterface interface {
+	// ApplyMergePatch applies a JSON Merge Patch (RFC 7396)
+	ApplyMergePatch(patch []byte) error
+
+	// ApplyPatch applies a JSON Patch (RFC 6902)
+	ApplyPatch(patch []byte) error
+}
+
+// Ensure the implementations provide the optional features
+var (
+	_ PathAccessorInterface    = (*PropertyObject)(nil)
+	_ TypedGetterInterface     = (*PropertyObject)(nil)
+	_ ChangeTrackerInterface   = (*PropertyObject)(nil)
+	_ ObservableInterface      = (*PropertyObject)(nil)
+	_ HistoryInterface         = (*PropertyObject)(nil)
+	_ SchemaValidatorInterface = (*PropertyObject)(nil)
+	_ NumberDecoderInterface   = (*SerializablePropertyObject)(nil)
+	_ PatchableInterface       = (*SerializablePropertyObject)(nil)
+)
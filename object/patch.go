@@ -0,0 +1,530 @@
+package object
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrPatchTestFailed is returned by ApplyPatch when a "test" operation does
+// not match the current value.
+var ErrPatchTestFailed = errors.New("object: patch test failed")
+
+// JSON Patch (RFC 6902) operation names.
+const (
+	PatchOpAdd     = "add"
+	PatchOpRemove  = "remove"
+	PatchOpReplace = "replace"
+	PatchOpMove    = "move"
+	PatchOpCopy    = "copy"
+	PatchOpTest    = "test"
+)
+
+// PatchOperation is a single JSON Patch (RFC 6902) operation. Path and From
+// are JSON Pointers (RFC 6901), e.g. "/address/city" or "/tags/0".
+type PatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// MarshalJSON writes the members the operation uses, always including
+// "value" for add, replace and test so that null values are kept.
+func (o PatchOperation) MarshalJSON() ([]byte, error) {
+	member := map[string]any{"op": o.Op, "path": o.Path}
+	switch o.Op {
+	case PatchOpMove, PatchOpCopy:
+		member["from"] = o.From
+	case PatchOpAdd, PatchOpReplace, PatchOpTest:
+		member["value"] = o.Value
+	}
+	return json.Marshal(member)
+}
+
+// ApplyMergePatch applies a JSON Merge Patch (RFC 7396): members of the
+// patch replace the current values, nested objects are merged and null
+// removes a member. The patch must be a JSON object. Like Diff, the patch
+// applies to the JSON form of the object, so typed values such as
+// map[string]string are merged as JSON objects; properties the patch changes
+// are stored as decoded JSON values, with numbers as json.Number. The object
+// is updated atomically and observers are notified of each changed
+// top-level key. When a schema is attached, the patched properties must pass
+// Validate, otherwise the object is left unchanged.
+func (s *SerializablePropertyObject) ApplyMergePatch(patch []byte) error {
+	decoded, err := decodeJSON(patch)
+	if err != nil {
+		return fmt.Errorf("object: invalid merge patch: %w", err)
+	}
+	if _, ok := decoded.(map[string]any); !ok {
+		return errors.New("object: merge patch must be a JSON object")
+	}
+
+	s.mutex.Lock()
+	document, err := s.jsonPropertiesLocked()
+	if err != nil {
+		s.mutex.Unlock()
+		return err
+	}
+	merged := mergePatch(cloneProperties(document), decoded).(map[string]any)
+	s.keepUnpatched(document, merged)
+	if err := s.validateLocked(merged); err != nil {
+		s.mutex.Unlock()
+		return err
+	}
+	changes, observers := s.replace(merged)
+	s.mutex.Unlock()
+
+	notify(changes, observers)
+	return nil
+}
+
+// ApplyPatch applies a JSON Patch (RFC 6902) document, a JSON array of
+// add, remove, replace, move, copy and test operations. Operations are
+// applied in order to the JSON form of the object, as produced by ToJSON,
+// which is only swapped in when all of them succeed and, when a schema is
+// attached, the result passes Validate, so a failing operation leaves the
+// object unchanged. As with ApplyMergePatch, changed properties are stored
+// as decoded JSON values.
+func (s *SerializablePropertyObject) ApplyPatch(patch []byte) error {
+	operations := []PatchOperation{}
+	decoder := json.NewDecoder(bytes.NewReader(patch))
+	decoder.UseNumber()
+	if err := decoder.Decode(&operations); err != nil {
+		return fmt.Errorf("object: invalid patch: %w", err)
+	}
+
+	s.mutex.Lock()
+	original, err := s.jsonPropertiesLocked()
+	if err != nil {
+		s.mutex.Unlock()
+		return err
+	}
+	var document any = cloneProperties(original)
+	for i, operation := range operations {
+		var err error
+		if document, err = applyPatchOperation(document, operation); err != nil {
+			s.mutex.Unlock()
+			return fmt.Errorf("object: patch operation %d (%s %q): %w", i, operation.Op, operation.Path, err)
+		}
+	}
+
+	properties, ok := document.(map[string]any)
+	if !ok {
+		s.mutex.Unlock()
+		return errors.New("object: patch must leave a JSON object")
+	}
+	s.keepUnpatched(original, properties)
+	if err := s.validateLocked(properties); err != nil {
+		s.mutex.Unlock()
+		return err
+	}
+	changes, observers := s.replace(properties)
+	s.mutex.Unlock()
+
+	notify(changes, observers)
+	return nil
+}
+
+// Diff compares the JSON forms of a and b and returns the JSON Patch that
+// turns a into b. Nested objects are compared member by member; arrays that
+// differ are replaced as a whole.
+//
+// Returns:
+//   - []byte: the JSON Patch document, "[]" when a and b are equal
+//   - error: If either object fails to serialize
+func Diff(a, b SerializableInterface) ([]byte, error) {
+	from, err := jsonDocument(a)
+	if err != nil {
+		return nil, err
+	}
+	to, err := jsonDocument(b)
+	if err != nil {
+		return nil, err
+	}
+
+	operations := []PatchOperation{}
+	diffDocument("", from, to, &operations)
+
+	return json.Marshal(operations)
+}
+
+// jsonDocument serializes object and decodes it to generic JSON values.
+func jsonDocument(object SerializableInterface) (any, error) {
+	data, err := object.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(data)
+}
+
+// jsonPropertiesLocked returns the properties in their JSON form, the
+// document the patches apply to. The caller must hold the lock.
+func (s *SerializablePropertyObject) jsonPropertiesLocked() (map[string]any, error) {
+	data, err := json.Marshal(s.properties)
+	if err != nil {
+		return nil, fmt.Errorf("object: serialize for patch: %w", err)
+	}
+	document, err := decodeJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	return document.(map[string]any), nil
+}
+
+// keepUnpatched restores the original Go values of the properties whose
+// JSON form a patch left unchanged, so they keep their types and do not
+// notify observers. The caller must hold the lock.
+func (s *SerializablePropertyObject) keepUnpatched(document, patched map[string]any) {
+	for key, value := range patched {
+		if before, exists := document[key]; exists && reflect.DeepEqual(before, value) {
+			patched[key] = s.properties[key]
+		}
+	}
+}
+
+// decodeJSON decodes data to generic JSON values, keeping numbers as
+// json.Number so large integers are not rounded.
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after the JSON value")
+	}
+	return document, nil
+}
+
+// diffDocument appends the operations turning from into to at pointer.
+func diffDocument(pointer string, from, to any, operations *[]PatchOperation) {
+	fromMap, fromIsMap := from.(map[string]any)
+	toMap, toIsMap := to.(map[string]any)
+
+	if !fromIsMap || !toIsMap {
+		if !reflect.DeepEqual(from, to) {
+			*operations = append(*operations, PatchOperation{Op: PatchOpReplace, Path: pointer, Value: to})
+		}
+		return
+	}
+
+	for _, key := range sortedMapKeys(fromMap) {
+		if _, exists := toMap[key]; !exists {
+			*operations = append(*operations, PatchOperation{Op: PatchOpRemove, Path: pointer + "/" + escapePointerToken(key)})
+		}
+	}
+
+	for _, key := range sortedMapKeys(toMap) {
+		child := pointer + "/" + escapePointerToken(key)
+		fromValue, exists := fromMap[key]
+		if !exists {
+			*operations = append(*operations, PatchOperation{Op: PatchOpAdd, Path: child, Value: toMap[key]})
+			continue
+		}
+		diffDocument(child, fromValue, toMap[key], operations)
+	}
+}
+
+// mergePatch applies patch to target as described in RFC 7396.
+func mergePatch(target, patch any) any {
+	patchMap, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetMap, ok := target.(map[string]any)
+	if !ok {
+		targetMap = map[string]any{}
+	}
+
+	for key, value := range patchMap {
+		if value == nil {
+			delete(targetMap, key)
+			continue
+		}
+		targetMap[key] = mergePatch(targetMap[key], value)
+	}
+
+	return targetMap
+}
+
+// applyPatchOperation applies operation to document and returns the new
+// document.
+func applyPatchOperation(document any, operation PatchOperation) (any, error) {
+	path, err := parsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case PatchOpAdd:
+		return pointerAdd(document, path, cloneValue(operation.Value))
+
+	case PatchOpRemove:
+		document, _, err = pointerRemove(document, path)
+		return document, err
+
+	case PatchOpReplace:
+		if _, err := pointerGet(document, path); err != nil {
+			return nil, err
+		}
+		if len(path) == 0 {
+			return cloneValue(operation.Value), nil
+		}
+		if document, _, err = pointerRemove(document, path); err != nil {
+			return nil, err
+		}
+		return pointerAdd(document, path, cloneValue(operation.Value))
+
+	case PatchOpMove:
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		if operation.Path == operation.From {
+			_, err := pointerGet(document, from)
+			return document, err
+		}
+		if strings.HasPrefix(operation.Path, operation.From+"/") {
+			return nil, errors.New("cannot move a value into one of its children")
+		}
+		document, value, err := pointerRemove(document, from)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(document, path, value)
+
+	case PatchOpCopy:
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := pointerGet(document, from)
+		if err != nil {
+			return nil, err
+		}
+		return pointerAdd(document, path, cloneValue(value))
+
+	case PatchOpTest:
+		value, err := pointerGet(document, path)
+		if err != nil {
+			return nil, err
+		}
+		if !jsonEqual(value, operation.Value) {
+			return nil, ErrPatchTestFailed
+		}
+		return document, nil
+
+	default:
+		return nil, fmt.Errorf("unknown operation %q", operation.Op)
+	}
+}
+
+// parsePointer splits a JSON Pointer into its unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// escapePointerToken escapes key for use in a JSON Pointer.
+func escapePointerToken(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// pointerIndex parses token as an array index below length. When allowEnd
+// is set, "-" and length itself refer to the end of the array.
+func pointerIndex(token string, length int, allowEnd bool) (int, error) {
+	if allowEnd && token == "-" {
+		return length, nil
+	}
+
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+
+	limit := length - 1
+	if allowEnd {
+		limit = length
+	}
+	if index > limit {
+		return 0, fmt.Errorf("array index %d out of range", index)
+	}
+
+	return index, nil
+}
+
+// pointerGet returns the value at path.
+func pointerGet(document any, path []string) (any, error) {
+	current := document
+	for _, token := range path {
+		switch container := current.(type) {
+		case map[string]any:
+			value, exists := container[token]
+			if !exists {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			current = value
+		case []any:
+			index, err := pointerIndex(token, len(container), false)
+			if err != nil {
+				return nil, err
+			}
+			current = container[index]
+		default:
+			return nil, fmt.Errorf("cannot reference %q in %T", token, current)
+		}
+	}
+	return current, nil
+}
+
+// pointerAdd adds value at path, inserting into arrays, and returns the
+// updated document.
+func pointerAdd(document any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	token, rest := path[0], path[1:]
+
+	switch container := document.(type) {
+	case map[string]any:
+		if len(rest) == 0 {
+			container[token] = value
+			return container, nil
+		}
+		child, exists := container[token]
+		if !exists {
+			return nil, fmt.Errorf("member %q not found", token)
+		}
+		updated, err := pointerAdd(child, rest, value)
+		if err != nil {
+			return nil, err
+		}
+		container[token] = updated
+		return container, nil
+
+	case []any:
+		if len(rest) == 0 {
+			index, err := pointerIndex(token, len(container), true)
+			if err != nil {
+				return nil, err
+			}
+			return append(container[:index:index], append([]any{value}, container[index:]...)...), nil
+		}
+		index, err := pointerIndex(token, len(container), false)
+		if err != nil {
+			return nil, err
+		}
+		updated, err := pointerAdd(container[index], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		container[index] = updated
+		return container, nil
+
+	default:
+		return nil, fmt.Errorf("cannot add %q to %T", token, document)
+	}
+}
+
+// pointerRemove removes the value at path and returns the updated document
+// together with the removed value.
+func pointerRemove(document any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, errors.New("cannot remove the whole document")
+	}
+
+	token, rest := path[0], path[1:]
+
+	switch container := document.(type) {
+	case map[string]any:
+		child, exists := container[token]
+		if !exists {
+			return nil, nil, fmt.Errorf("member %q not found", token)
+		}
+		if len(rest) == 0 {
+			delete(container, token)
+			return container, child, nil
+		}
+		updated, removed, err := pointerRemove(child, rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		container[token] = updated
+		return container, removed, nil
+
+	case []any:
+		index, err := pointerIndex(token, len(container), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(rest) == 0 {
+			removed := container[index]
+			return append(container[:index:index], container[index+1:]...), removed, nil
+		}
+		updated, removed, err := pointerRemove(container[index], rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		container[index] = updated
+		return container, removed, nil
+
+	default:
+		return nil, nil, fmt.Errorf("cannot remove %q from %T", token, document)
+	}
+}
+
+// jsonEqual reports whether a and b have the same JSON representation, so
+// that e.g. int 1 equals the float64 1 decoded from a patch.
+func jsonEqual(a, b any) bool {
+	normalize := func(value any) (any, bool) {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, false
+		}
+		var decoded any
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return nil, false
+		}
+		return decoded, true
+	}
+
+	left, ok := normalize(a)
+	if !ok {
+		return false
+	}
+	right, ok := normalize(b)
+	if !ok {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
+
+// sortedMapKeys returns the keys of values in sorted order.
+func sortedMapKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
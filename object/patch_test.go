@@ -0,0 +1,281 @@
+package object_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dracory/base/object"
+)
+
+func newPatchObject(t *testing.T, data string) *object.SerializablePropertyObject {
+	t.Helper()
+	spo := object.NewSerializablePropertyObject().(*object.SerializablePropertyObject)
+	if err := spo.FromJSON([]byte(data)); err != nil {
+		t.Fatalf("FromJSON failed: %v", err)
+	}
+	return spo
+}
+
+func documentOf(t *testing.T, spo object.SerializableInterface) map[string]any {
+	t.Helper()
+	data, err := spo.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	document := map[string]any{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		t.Fatal(err)
+	}
+	return document
+}
+
+func TestSerializablePropertyObject_ApplyMergePatch(t *testing.T) {
+	// Example from RFC 7396, section 3
+	spo := newPatchObject(t, `{
+		"title": "Goodbye!",
+		"author": {"givenName": "John", "familyName": "Doe"},
+		"tags": ["example", "sample"],
+		"content": "This will be unchanged"
+	}`)
+
+	var changed []string
+	spo.OnAnyChange(func(key string, _, _ any) { changed = append(changed, key) })
+
+	err := spo.ApplyMergePatch([]byte(`{
+		"title": "Hello!",
+		"phoneNumber": "+01-123-456-7890",
+		"author": {"familyName": null},
+		"tags": ["example"]
+	}`))
+	if err != nil {
+		t.Fatalf("ApplyMergePatch failed: %v", err)
+	}
+
+	want := map[string]any{
+		"title":       "Hello!",
+		"author":      map[string]any{"givenName": "John"},
+		"tags":        []any{"example"},
+		"content":     "This will be unchanged",
+		"phoneNumber": "+01-123-456-7890",
+	}
+	if got := documentOf(t, spo); !reflect.DeepEqual(got, want) {
+		t.Errorf("document = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(changed, []string{"author", "phoneNumber", "tags", "title"}) {
+		t.Errorf("observers notified for %v", changed)
+	}
+
+	for _, patch := range []string{`["not", "an", "object"]`, `"text"`, `{`} {
+		if err := spo.ApplyMergePatch([]byte(patch)); err == nil {
+			t.Errorf("ApplyMergePatch(%s) should fail", patch)
+		}
+	}
+}
+
+func TestSerializablePropertyObject_ApplyPatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"add member", `{"foo": "bar"}`, `[{"op": "add", "path": "/baz", "value": "qux"}]`, `{"foo": "bar", "baz": "qux"}`},
+		{"add array element", `{"foo": ["bar", "baz"]}`, `[{"op": "add", "path": "/foo/1", "value": "qux"}]`, `{"foo": ["bar", "qux", "baz"]}`},
+		{"append to array", `{"foo": ["bar"]}`, `[{"op": "add", "path": "/foo/-", "value": {"a": 1}}]`, `{"foo": ["bar", {"a": 1}]}`},
+		{"add null", `{}`, `[{"op": "add", "path": "/foo", "value": null}]`, `{"foo": null}`},
+		{"remove member", `{"baz": "qux", "foo": "bar"}`, `[{"op": "remove", "path": "/baz"}]`, `{"foo": "bar"}`},
+		{"remove array element", `{"foo": ["bar", "qux", "baz"]}`, `[{"op": "remove", "path": "/foo/1"}]`, `{"foo": ["bar", "baz"]}`},
+		{"replace", `{"baz": "qux", "foo": "bar"}`, `[{"op": "replace", "path": "/baz", "value": "boo"}]`, `{"baz": "boo", "foo": "bar"}`},
+		{"move member", `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`, `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`, `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`},
+		{"move array element", `{"foo": ["all", "grass", "cows", "eat"]}`, `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`, `{"foo": ["all", "cows", "eat", "grass"]}`},
+		{"copy", `{"a": {"b": 1}}`, `[{"op": "copy", "from": "/a", "path": "/c"}, {"op": "replace", "path": "/c/b", "value": 2}]`, `{"a": {"b": 1}, "c": {"b": 2}}`},
+		{"test then replace", `{"baz": "qux", "foo": ["a", 2, "c"]}`, `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}, {"op": "replace", "path": "/baz", "value": "ok"}]`, `{"baz": "ok", "foo": ["a", 2, "c"]}`},
+		{"escaped pointer", `{"a/b": 1, "m~n": 2}`, `[{"op": "replace", "path": "/a~1b", "value": 3}, {"op": "remove", "path": "/m~0n"}]`, `{"a/b": 3}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spo := newPatchObject(t, tt.doc)
+			if err := spo.ApplyPatch([]byte(tt.patch)); err != nil {
+				t.Fatalf("ApplyPatch failed: %v", err)
+			}
+
+			want := map[string]any{}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if got := documentOf(t, spo); !reflect.DeepEqual(got, want) {
+				t.Errorf("document = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestSerializablePropertyObject_ApplyPatchErrors(t *testing.T) {
+	original := `{"foo": "bar", "list": [1, 2]}`
+
+	tests := []struct {
+		name  string
+		patch string
+	}{
+		{"failed test", `[{"op": "replace", "path": "/foo", "value": "changed"}, {"op": "test", "path": "/foo", "value": "bar"}]`},
+		{"missing member", `[{"op": "remove", "path": "/missing"}]`},
+		{"replace missing", `[{"op": "replace", "path": "/missing", "value": 1}]`},
+		{"missing parent", `[{"op": "add", "path": "/a/b", "value": 1}]`},
+		{"index out of range", `[{"op": "add", "path": "/list/5", "value": 1}]`},
+		{"leading zero index", `[{"op": "remove", "path": "/list/01"}]`},
+		{"move into child", `[{"op": "move", "from": "/list", "path": "/list/0"}]`},
+		{"invalid pointer", `[{"op": "add", "path": "foo", "value": 1}]`},
+		{"unknown operation", `[{"op": "merge", "path": "/foo"}]`},
+		{"root not object", `[{"op": "replace", "path": "", "value": [1]}]`},
+		{"remove root", `[{"op": "remove", "path": ""}]`},
+		{"invalid json", `{"op": "add"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spo := newPatchObject(t, original)
+			before := documentOf(t, spo)
+
+			if err := spo.ApplyPatch([]byte(tt.patch)); err == nil {
+				t.Fatal("ApplyPatch should fail")
+			}
+			if got := documentOf(t, spo); !reflect.DeepEqual(got, before) {
+				t.Errorf("failed patch modified the object: %v", got)
+			}
+		})
+	}
+
+	spo := newPatchObject(t, original)
+	err := spo.ApplyPatch([]byte(`[{"op": "test", "path": "/foo", "value": "baz"}]`))
+	if !errors.Is(err, object.ErrPatchTestFailed) {
+		t.Errorf("error = %v, want ErrPatchTestFailed", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := newPatchObject(t, `{"id": "1", "title": "Draft", "author": {"name": "John", "email": "john@example.com"}, "tags": ["a"], "obsolete": true}`)
+	b := newPatchObject(t, `{"id": "1", "title": "Published", "author": {"name": "John", "phone": "123"}, "tags": ["a", "b"], "notes": null}`)
+
+	patch, err := object.Diff(a, b)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	operations := []object.PatchOperation{}
+	if err := json.Unmarshal(patch, &operations); err != nil {
+		t.Fatalf("Diff produced invalid JSON: %v", err)
+	}
+	want := []object.PatchOperation{
+		{Op: "remove", Path: "/obsolete"},
+		{Op: "remove", Path: "/author/email"},
+		{Op: "add", Path: "/author/phone", Value: "123"},
+		{Op: "add", Path: "/notes", Value: nil},
+		{Op: "replace", Path: "/tags", Value: []any{"a", "b"}},
+		{Op: "replace", Path: "/title", Value: "Published"},
+	}
+	if !reflect.DeepEqual(operations, want) {
+		t.Errorf("Diff() = %s", patch)
+	}
+
+	if err := a.ApplyPatch(patch); err != nil {
+		t.Fatalf("ApplyPatch(Diff) failed: %v", err)
+	}
+	if !reflect.DeepEqual(documentOf(t, a), documentOf(t, b)) {
+		t.Errorf("applying the diff did not produce b: %v", documentOf(t, a))
+	}
+
+	empty, err := object.Diff(a, b)
+	if err != nil || string(empty) != "[]" {
+		t.Errorf("Diff of equal objects = %s, %v; want []", empty, err)
+	}
+}
+
+func TestSerializablePropertyObject_PatchWithSchema(t *testing.T) {
+	schema, err := object.NewPropertySchema(
+		object.PropertySpec{Key: "name", Type: object.PropertyTypeString},
+		object.PropertySpec{Key: "age", Type: object.PropertyTypeInt, Min: object.Bound(0), Max: object.Bound(150)},
+	)
+	if err != nil {
+		t.Fatalf("NewPropertySchema failed: %v", err)
+	}
+
+	spo := newPatchObject(t, `{"id": "1", "name": "John", "age": 30}`)
+	spo.SetSchema(schema)
+
+	rejected := map[string]func() error{
+		"merge wrong type":   func() error { return spo.ApplyMergePatch([]byte(`{"age": "old"}`)) },
+		"merge undeclared":   func() error { return spo.ApplyMergePatch([]byte(`{"admin": true}`)) },
+		"patch out of range": func() error { return spo.ApplyPatch([]byte(`[{"op": "replace", "path": "/age", "value": -5.5}]`)) },
+		"patch undeclared":   func() error { return spo.ApplyPatch([]byte(`[{"op": "add", "path": "/admin", "value": true}]`)) },
+	}
+	for name, apply := range rejected {
+		var propertyErr *object.PropertyError
+		if err := apply(); !errors.As(err, &propertyErr) {
+			t.Errorf("%s: error = %v, want a *PropertyError", name, err)
+		}
+	}
+
+	want := map[string]any{"id": "1", "name": "John", "age": float64(30)}
+	if got := documentOf(t, spo); !reflect.DeepEqual(got, want) {
+		t.Errorf("rejected patches changed the object: %v", got)
+	}
+
+	if err := spo.ApplyMergePatch([]byte(`{"age": 31}`)); err != nil {
+		t.Errorf("valid merge patch failed: %v", err)
+	}
+}
+
+func TestSerializablePropertyObject_PatchTypedValues(t *testing.T) {
+	newTyped := func() *object.SerializablePropertyObject {
+		spo := object.NewSerializablePropertyObject().(*object.SerializablePropertyObject)
+		spo.SetID("1")
+		spo.Set("address", map[string]string{"city": "Sofia", "zip": "1000"})
+		spo.Set("tags", []string{"a"})
+		spo.Set("age", 30)
+		return spo
+	}
+
+	merged := newTyped()
+	if err := merged.ApplyMergePatch([]byte(`{"address": {"city": "Varna"}}`)); err != nil {
+		t.Fatalf("ApplyMergePatch failed: %v", err)
+	}
+	want := map[string]any{"city": "Varna", "zip": "1000"}
+	if got := documentOf(t, merged)["address"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("address = %v, want %v", got, want)
+	}
+	if got := merged.Get("age"); got != 30 {
+		t.Errorf("unpatched age = %#v, want the original int", got)
+	}
+
+	patched := newTyped()
+	err := patched.ApplyPatch([]byte(`[
+		{"op": "replace", "path": "/address/city", "value": "Plovdiv"},
+		{"op": "add", "path": "/tags/-", "value": "b"}
+	]`))
+	if err != nil {
+		t.Fatalf("ApplyPatch failed: %v", err)
+	}
+	document := documentOf(t, patched)
+	if !reflect.DeepEqual(document["address"], map[string]any{"city": "Plovdiv", "zip": "1000"}) || !reflect.DeepEqual(document["tags"], []any{"a", "b"}) {
+		t.Errorf("document = %v", document)
+	}
+
+	a := newTyped()
+	b := newTyped()
+	b.Set("address", map[string]string{"city": "Burgas", "zip": "1000"})
+	b.Set("tags", []string{"a", "c"})
+
+	patch, err := object.Diff(a, b)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if err := a.ApplyPatch(patch); err != nil {
+		t.Fatalf("ApplyPatch(Diff) failed: %v", err)
+	}
+	if !reflect.DeepEqual(documentOf(t, a), documentOf(t, b)) {
+		t.Errorf("applying the diff did not produce b: %v", documentOf(t, a))
+	}
+}
@@ -8,6 +8,26 @@ import (
 type PropertyObject struct {
 	properties map[string]any
 	mutex      sync.RWMutex
+
+	// change tracking, see EnableChangeTracking
+	tracking bool
+	original map[string]any
+
+	// observers registered with OnChange and OnAnyChange
+	observers      []propertyObserver
+	nextObserverID int
+
+	// checkpoints for Undo, oldest first
+	history         []map[string]any
+	historyLimit    int
+	historyLimitSet bool
+
+	// schema checked by Set, see SetSchema
+	schema *PropertySchema
+
+	// idKey is accepted by the schema even when undeclared, "id" for
+	// serializable objects
+	idKey string
 }
 
 // NewPropertyObject creates a new PropertyObject
@@ -27,8 +47,10 @@ func (p *PropertyObject) Count() int {
 // Clear removes all properties
 func (p *PropertyObject) Clear() {
 	p.mutex.Lock()
-	defer p.mutex.Unlock()
-	p.properties = make(map[string]any)
+	changes, observers := p.replace(make(map[string]any))
+	p.mutex.Unlock()
+
+	notify(changes, observers)
 }
 
 // Get retrieves a property value by key
@@ -61,17 +83,33 @@ func (p *PropertyObject) Keys() []string {
 	return keys
 }
 
-// Set stores a property value with the given key
+// Set stores a property value with the given key. When a schema is
+// attached, a value that does not match it is rejected with a
+// *PropertyError.
 func (p *PropertyObject) Set(key string, value any) error {
 	p.mutex.Lock()
-	defer p.mutex.Unlock()
+	if err := p.checkLocked(key, value); err != nil {
+		p.mutex.Unlock()
+		return err
+	}
+	changes, observers := p.pendingChange(key, p.properties[key], value)
 	p.properties[key] = value
+	p.mutex.Unlock()
+
+	notify(changes, observers)
 	return nil
 }
 
 // Unset removes a property by key
 func (p *PropertyObject) Unset(key string) {
 	p.mutex.Lock()
-	defer p.mutex.Unlock()
+	var changes []propertyChange
+	var observers []propertyObserver
+	if old, exists := p.properties[key]; exists {
+		changes, observers = p.pendingChange(key, old, nil)
+	}
 	delete(p.properties, key)
+	p.mutex.Unlock()
+
+	notify(changes, observers)
 }
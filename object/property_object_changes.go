@@ -0,0 +1,181 @@
+package object
+
+import (
+	"reflect"
+	"sort"
+)
+
+// propertyObserver is a callback registered with OnChange or OnAnyChange.
+type propertyObserver struct {
+	id       int
+	key      string
+	anyKey   bool
+	callback func(key string, oldValue, newValue any)
+}
+
+// propertyChange is a change waiting to be delivered to observers.
+type propertyChange struct {
+	key      string
+	oldValue any
+	newValue any
+}
+
+// EnableChangeTracking starts tracking changes, taking the current
+// properties as the original values. Calling it again resets the baseline
+// like MarkClean.
+func (p *PropertyObject) EnableChangeTracking() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.tracking = true
+	p.original = cloneProperties(p.properties)
+}
+
+// IsDirty reports whether any property differs from its original value.
+// It is always false when change tracking is not enabled.
+func (p *PropertyObject) IsDirty() bool {
+	return len(p.DirtyKeys()) > 0
+}
+
+// DirtyKeys returns, in sorted order, the keys that were added, removed or
+// changed since tracking was enabled or MarkClean was last called.
+func (p *PropertyObject) DirtyKeys() []string {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if !p.tracking {
+		return []string{}
+	}
+
+	keys := []string{}
+	for key, value := range p.properties {
+		original, existed := p.original[key]
+		if !existed || !reflect.DeepEqual(original, value) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range p.original {
+		if _, exists := p.properties[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// OriginalValue returns the value key had when tracking was enabled or
+// MarkClean was last called, and whether it existed then.
+func (p *PropertyObject) OriginalValue(key string) (any, bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if !p.tracking {
+		return nil, false
+	}
+
+	value, exists := p.original[key]
+	return cloneValue(value), exists
+}
+
+// MarkClean takes the current properties as the new original values, e.g.
+// after the object was persisted.
+func (p *PropertyObject) MarkClean() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.tracking {
+		p.original = cloneProperties(p.properties)
+	}
+}
+
+// OnChange registers callback to be called after the property key is
+// changed by Set, Unset or Clear, or a path below it by SetPath or UnsetPath.
+// Callbacks run after the lock is released, so they may read and modify
+// the object.
+//
+// Returns:
+//   - func(): removes the callback
+func (p *PropertyObject) OnChange(key string, callback func(oldValue, newValue any)) func() {
+	return p.observe(propertyObserver{key: key, callback: func(_ string, oldValue, newValue any) {
+		callback(oldValue, newValue)
+	}})
+}
+
+// OnAnyChange registers callback to be called after any property changes.
+//
+// Returns:
+//   - func(): removes the callback
+func (p *PropertyObject) OnAnyChange(callback func(key string, oldValue, newValue any)) func() {
+	return p.observe(propertyObserver{anyKey: true, callback: callback})
+}
+
+// observe stores observer and returns its removal function.
+func (p *PropertyObject) observe(observer propertyObserver) func() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.nextObserverID++
+	observer.id = p.nextObserverID
+	p.observers = append(p.observers, observer)
+
+	return func() {
+		p.mutex.Lock()
+		defer p.mutex.Unlock()
+
+		for i, existing := range p.observers {
+			if existing.id == observer.id {
+				p.observers = append(p.observers[:i:i], p.observers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// pendingChange returns the change from oldValue to newValue together with
+// the observers to notify, or nils when no observer is registered or the
+// value did not change. The caller must hold the lock.
+func (p *PropertyObject) pendingChange(key string, oldValue, newValue any) ([]propertyChange, []propertyObserver) {
+	if len(p.observers) == 0 || reflect.DeepEqual(oldValue, newValue) {
+		return nil, nil
+	}
+	return []propertyChange{{key: key, oldValue: oldValue, newValue: newValue}}, p.observers
+}
+
+// notify delivers changes to the matching observers. It must be called
+// without holding the lock.
+func notify(changes []propertyChange, observers []propertyObserver) {
+	for _, change := range changes {
+		for _, observer := range observers {
+			if observer.anyKey || observer.key == change.key {
+				observer.callback(change.key, change.oldValue, change.newValue)
+			}
+		}
+	}
+}
+
+// cloneProperties returns a deep copy of properties.
+func cloneProperties(properties map[string]any) map[string]any {
+	clone := make(map[string]any, len(properties))
+	for key, value := range properties {
+		clone[key] = cloneValue(value)
+	}
+	return clone
+}
+
+// cloneValue deep copies the maps and slices produced by JSON decoding, so
+// nested edits do not leak into a saved copy. Other values are returned as
+// they are.
+func cloneValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return cloneProperties(v)
+	case []any:
+		clone := make([]any, len(v))
+		for i, item := range v {
+			clone[i] = cloneValue(item)
+		}
+		return clone
+	default:
+		return value
+	}
+}
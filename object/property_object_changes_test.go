@@ -0,0 +1,154 @@
+package object_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/dracory/base/object"
+)
+
+func Test_PropertyObject_ChangeTracking(t *testing.T) {
+	po := object.NewPropertyObject().(*object.PropertyObject)
+	po.Set("name", "John")
+	po.Set("address", map[string]any{"city": "Sofia"})
+	po.Set("age", 30)
+
+	if po.IsDirty() || len(po.DirtyKeys()) != 0 {
+		t.Error("an object without change tracking should never be dirty")
+	}
+	if _, exists := po.OriginalValue("name"); exists {
+		t.Error("OriginalValue should report nothing without change tracking")
+	}
+
+	po.EnableChangeTracking()
+	if po.IsDirty() {
+		t.Fatalf("object should be clean after EnableChangeTracking, dirty: %v", po.DirtyKeys())
+	}
+
+	po.Set("name", "Jane")
+	po.Set("email", "jane@example.com")
+	po.Unset("age")
+	po.SetPath("address.city", "Plovdiv")
+
+	want := []string{"address", "age", "email", "name"}
+	if got := po.DirtyKeys(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DirtyKeys() = %v, want %v", got, want)
+	}
+	if !po.IsDirty() {
+		t.Error("IsDirty() = false, want true")
+	}
+
+	if original, exists := po.OriginalValue("name"); !exists || original != "John" {
+		t.Errorf("OriginalValue(name) = %v, %v; want John, true", original, exists)
+	}
+	if original, _ := po.OriginalValue("address"); !reflect.DeepEqual(original, map[string]any{"city": "Sofia"}) {
+		t.Errorf("OriginalValue(address) = %v, nested edits must not change the original", original)
+	}
+	if _, exists := po.OriginalValue("email"); exists {
+		t.Error("OriginalValue(email) should not exist for an added key")
+	}
+
+	// Restoring the original value makes the key clean again
+	po.Set("name", "John")
+	if got := po.DirtyKeys(); reflect.DeepEqual(got, want) {
+		t.Errorf("DirtyKeys() = %v, name should be clean again", got)
+	}
+
+	po.MarkClean()
+	if po.IsDirty() {
+		t.Errorf("object should be clean after MarkClean, dirty: %v", po.DirtyKeys())
+	}
+	if original, _ := po.OriginalValue("email"); original != "jane@example.com" {
+		t.Errorf("OriginalValue(email) after MarkClean = %v", original)
+	}
+}
+
+func Test_PropertyObject_OnChange(t *testing.T) {
+	po := object.NewPropertyObject().(*object.PropertyObject)
+	po.Set("name", "John")
+
+	type change struct {
+		key      string
+		old, new any
+	}
+
+	var nameChanges []change
+	var allChanges []change
+	removeName := po.OnChange("name", func(oldValue, newValue any) {
+		nameChanges = append(nameChanges, change{"name", oldValue, newValue})
+	})
+	po.OnAnyChange(func(key string, oldValue, newValue any) {
+		allChanges = append(allChanges, change{key, oldValue, newValue})
+		// Observers run outside the lock and may read the object
+		po.Get(key)
+	})
+
+	po.Set("name", "Jane")
+	po.Set("name", "Jane") // unchanged, not reported
+	po.Set("age", 30)
+	po.Unset("age")
+	po.Unset("missing") // not present, not reported
+	po.SetPath("address.city", "Sofia")
+	po.SetPath("address.zip", "1000")
+	po.UnsetPath("address.zip")
+
+	wantName := []change{{"name", "John", "Jane"}}
+	if !reflect.DeepEqual(nameChanges, wantName) {
+		t.Errorf("name changes = %v, want %v", nameChanges, wantName)
+	}
+
+	removeName()
+	po.Clear()
+
+	if len(nameChanges) != 1 {
+		t.Errorf("removed observer was called: %v", nameChanges)
+	}
+
+	wantAll := []change{
+		{"name", "John", "Jane"},
+		{"age", nil, 30},
+		{"age", 30, nil},
+		{"address", nil, map[string]any{"city": "Sofia"}},
+		{"address", map[string]any{"city": "Sofia"}, map[string]any{"city": "Sofia", "zip": "1000"}},
+		{"address", map[string]any{"city": "Sofia", "zip": "1000"}, map[string]any{"city": "Sofia"}},
+		{"address", map[string]any{"city": "Sofia"}, nil},
+		{"name", "Jane", nil},
+	}
+	if !reflect.DeepEqual(allChanges, wantAll) {
+		t.Errorf("all changes = %v, want %v", allChanges, wantAll)
+	}
+}
+
+func Test_PropertyObject_ObserverConcurrency(t *testing.T) {
+	po := object.NewPropertyObject().(*object.PropertyObject)
+	po.EnableChangeTracking()
+
+	var mu sync.Mutex
+	count := 0
+	po.OnAnyChange(func(string, any, any) {
+		mu.Lock()
+		count++
+		mu.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for j := range 50 {
+				po.Set("key", id*100+j)
+				po.DirtyKeys()
+				if j%10 == 0 {
+					po.MarkClean()
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if count == 0 {
+		t.Error("observer was never called")
+	}
+}
@@ -0,0 +1,152 @@
+package object
+
+import "sort"
+
+// DefaultHistoryLimit is the number of checkpoints kept for Undo unless
+// changed with SetHistoryLimit.
+const DefaultHistoryLimit = 10
+
+// Snapshot is a point-in-time copy of the properties of an object, taken
+// with Snapshot and applied with Restore. Nested maps and slices are deep
+// copied; other reference values, such as pointers, are shared.
+type Snapshot struct {
+	properties map[string]any
+}
+
+// Keys returns the keys held by the snapshot in sorted order.
+func (s Snapshot) Keys() []string {
+	keys := make([]string, 0, len(s.properties))
+	for k := range s.properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Get returns the value of key in the snapshot.
+func (s Snapshot) Get(key string) any {
+	return cloneValue(s.properties[key])
+}
+
+// Snapshot returns a copy of the current properties.
+func (p *PropertyObject) Snapshot() Snapshot {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return Snapshot{properties: cloneProperties(p.properties)}
+}
+
+// Restore atomically replaces all properties with those of snapshot.
+// Observers are notified of every key whose value changes. Change tracking
+// keeps its original values, so restoring them makes the object clean.
+// When a schema is attached, the snapshot must pass Validate, since it may
+// come from another object; otherwise the object is left unchanged.
+func (p *PropertyObject) Restore(snapshot Snapshot) error {
+	properties := cloneProperties(snapshot.properties)
+
+	p.mutex.Lock()
+	if err := p.validateLocked(properties); err != nil {
+		p.mutex.Unlock()
+		return err
+	}
+	changes, observers := p.replace(properties)
+	p.mutex.Unlock()
+
+	notify(changes, observers)
+	return nil
+}
+
+// Checkpoint records the current properties so a following batch of changes
+// can be rolled back with Undo. Only the most recent checkpoints are kept,
+// see SetHistoryLimit.
+func (p *PropertyObject) Checkpoint() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.history = append(p.history, cloneProperties(p.properties))
+	if limit := p.historyLimitLocked(); len(p.history) > limit {
+		p.history = append([]map[string]any(nil), p.history[len(p.history)-limit:]...)
+	}
+}
+
+// Undo atomically restores the most recent checkpoint and removes it from
+// the history. The schema is not checked: checkpoints hold states of this
+// object, so rolling back must always succeed, even after SetSchema.
+//
+// Returns:
+//   - bool: false when there is no checkpoint to restore
+func (p *PropertyObject) Undo() bool {
+	p.mutex.Lock()
+	if len(p.history) == 0 {
+		p.mutex.Unlock()
+		return false
+	}
+
+	last := p.history[len(p.history)-1]
+	p.history = p.history[:len(p.history)-1]
+	changes, observers := p.replace(last)
+	p.mutex.Unlock()
+
+	notify(changes, observers)
+	return true
+}
+
+// HistoryLen returns the number of checkpoints available to Undo.
+func (p *PropertyObject) HistoryLen() int {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return len(p.history)
+}
+
+// SetHistoryLimit sets how many checkpoints are kept, dropping the oldest
+// ones beyond the limit. A limit below 1 disables the history.
+func (p *PropertyObject) SetHistoryLimit(limit int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.historyLimit = max(limit, 0)
+	p.historyLimitSet = true
+	if len(p.history) > p.historyLimit {
+		p.history = append([]map[string]any(nil), p.history[len(p.history)-p.historyLimit:]...)
+	}
+}
+
+// historyLimitLocked returns the effective history limit. The caller must
+// hold the lock.
+func (p *PropertyObject) historyLimitLocked() int {
+	if !p.historyLimitSet {
+		return DefaultHistoryLimit
+	}
+	return p.historyLimit
+}
+
+// replace swaps in properties and returns the changes to report. The caller
+// must hold the lock.
+func (p *PropertyObject) replace(properties map[string]any) ([]propertyChange, []propertyObserver) {
+	previous := p.properties
+	p.properties = properties
+
+	keys := map[string]bool{}
+	for k := range previous {
+		keys[k] = true
+	}
+	for k := range properties {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	changes := []propertyChange{}
+	var observers []propertyObserver
+	for _, k := range sorted {
+		change, matched := p.pendingChange(k, previous[k], properties[k])
+		changes = append(changes, change...)
+		if matched != nil {
+			observers = matched
+		}
+	}
+
+	return changes, observers
+}
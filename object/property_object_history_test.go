@@ -0,0 +1,163 @@
+package object_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/dracory/base/object"
+)
+
+func Test_PropertyObject_SnapshotRestore(t *testing.T) {
+	po := object.NewPropertyObject().(*object.PropertyObject)
+	po.Set("name", "John")
+	po.Set("address", map[string]any{"city": "Sofia"})
+
+	snapshot := po.Snapshot()
+	if got := snapshot.Keys(); !reflect.DeepEqual(got, []string{"address", "name"}) {
+		t.Errorf("Snapshot.Keys() = %v", got)
+	}
+
+	po.Set("name", "Jane")
+	po.Set("email", "jane@example.com")
+	po.SetPath("address.city", "Plovdiv")
+
+	if got := snapshot.Get("address"); !reflect.DeepEqual(got, map[string]any{"city": "Sofia"}) {
+		t.Errorf("snapshot changed by nested edit: %v", got)
+	}
+
+	var changed []string
+	po.OnAnyChange(func(key string, _, _ any) { changed = append(changed, key) })
+
+	if err := po.Restore(snapshot); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+
+	if po.Get("name") != "John" || po.Has("email") || po.GetPath("address.city") != "Sofia" {
+		t.Errorf("Restore did not restore the snapshot: name=%v email=%v city=%v", po.Get("name"), po.Get("email"), po.GetPath("address.city"))
+	}
+	if !reflect.DeepEqual(changed, []string{"address", "email", "name"}) {
+		t.Errorf("observers notified for %v", changed)
+	}
+
+	// Editing after a restore must not change the snapshot
+	po.SetPath("address.city", "Varna")
+	po.Restore(snapshot)
+	if po.GetPath("address.city") != "Sofia" {
+		t.Error("snapshot must be reusable after a restore")
+	}
+}
+
+func Test_PropertyObject_Undo(t *testing.T) {
+	po := object.NewPropertyObject().(*object.PropertyObject)
+	po.Set("title", "Draft")
+
+	if po.Undo() {
+		t.Error("Undo() without checkpoints should return false")
+	}
+
+	po.Checkpoint()
+	po.Set("title", "Published")
+	po.Set("published", true)
+
+	po.Checkpoint()
+	po.Unset("title")
+
+	if po.HistoryLen() != 2 {
+		t.Fatalf("HistoryLen() = %d, want 2", po.HistoryLen())
+	}
+
+	if !po.Undo() || po.Get("title") != "Published" {
+		t.Errorf("first Undo() title = %v, want Published", po.Get("title"))
+	}
+	if !po.Undo() || po.Get("title") != "Draft" || po.Has("published") {
+		t.Errorf("second Undo() title = %v, published = %v", po.Get("title"), po.Get("published"))
+	}
+	if po.HistoryLen() != 0 || po.Undo() {
+		t.Error("history should be empty")
+	}
+}
+
+func Test_PropertyObject_HistoryLimit(t *testing.T) {
+	po := object.NewPropertyObject().(*object.PropertyObject)
+
+	for i := range object.DefaultHistoryLimit + 5 {
+		po.Set("step", i)
+		po.Checkpoint()
+	}
+	if po.HistoryLen() != object.DefaultHistoryLimit {
+		t.Errorf("HistoryLen() = %d, want %d", po.HistoryLen(), object.DefaultHistoryLimit)
+	}
+
+	po.SetHistoryLimit(3)
+	if po.HistoryLen() != 3 {
+		t.Fatalf("HistoryLen() = %d, want 3", po.HistoryLen())
+	}
+
+	// The newest checkpoints are kept
+	po.Undo()
+	if got := po.Get("step"); got != object.DefaultHistoryLimit+4 {
+		t.Errorf("step = %v, want %d", got, object.DefaultHistoryLimit+4)
+	}
+
+	po.SetHistoryLimit(0)
+	po.Checkpoint()
+	if po.HistoryLen() != 0 {
+		t.Errorf("HistoryLen() = %d, want 0 with history disabled", po.HistoryLen())
+	}
+}
+
+func TestSerializablePropertyObject_ConcurrentJSON(t *testing.T) {
+	spo := object.NewSerializablePropertyObject().(*object.SerializablePropertyObject)
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			for j := range 50 {
+				spo.Set("key", id*100+j)
+				data, err := spo.ToJSON()
+				if err != nil {
+					t.Errorf("ToJSON failed: %v", err)
+					return
+				}
+				if j%10 == 0 {
+					if err := spo.FromJSON(data); err != nil {
+						t.Errorf("FromJSON failed: %v", err)
+						return
+					}
+					spo.FromJSONWithNumbers(data)
+				}
+				spo.Checkpoint()
+				spo.Snapshot()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if spo.GetID() == "" {
+		t.Error("ID lost during concurrent serialization")
+	}
+}
+
+func Test_PropertyObject_RestoreWithSchema(t *testing.T) {
+	other := object.NewPropertyObject().(*object.PropertyObject)
+	other.Set("admin", true)
+
+	schema, err := object.NewPropertySchema(object.PropertySpec{Key: "name", Type: object.PropertyTypeString})
+	if err != nil {
+		t.Fatalf("NewPropertySchema failed: %v", err)
+	}
+
+	po := object.NewPropertyObject().(*object.PropertyObject)
+	po.SetSchema(schema)
+	po.Set("name", "John")
+
+	if err := po.Restore(other.Snapshot()); err == nil {
+		t.Error("Restore should reject a snapshot not matching the schema")
+	}
+	if po.Get("name") != "John" || po.Has("admin") {
+		t.Errorf("rejected Restore changed the object: name=%v admin=%v", po.Get("name"), po.Get("admin"))
+	}
+}
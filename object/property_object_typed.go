@@ -0,0 +1,143 @@
+package object
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// GetString returns the property as a string, or defaultValue when it is
+// missing, nil or cannot be converted.
+func (p *PropertyObject) GetString(key string, defaultValue string) string {
+	return getTyped(p, key, defaultValue, cast.ToStringE)
+}
+
+// GetInt returns the property as an int, or defaultValue when it is missing,
+// nil or cannot be converted. Strings are parsed in base 10 ("010" is 10),
+// and floats with a fractional part are not converted.
+func (p *PropertyObject) GetInt(key string, defaultValue int) int {
+	return getTyped(p, key, defaultValue, toIntE)
+}
+
+// GetInt64 returns the property as an int64, or defaultValue when it is
+// missing, nil or cannot be converted. Strings are parsed in base 10, and
+// floats with a fractional part are not converted.
+func (p *PropertyObject) GetInt64(key string, defaultValue int64) int64 {
+	return getTyped(p, key, defaultValue, toInt64E)
+}
+
+// GetFloat returns the property as a float64, or defaultValue when it is
+// missing, nil or cannot be converted.
+func (p *PropertyObject) GetFloat(key string, defaultValue float64) float64 {
+	return getTyped(p, key, defaultValue, cast.ToFloat64E)
+}
+
+// GetBool returns the property as a bool, or defaultValue when it is
+// missing, nil or cannot be converted.
+func (p *PropertyObject) GetBool(key string, defaultValue bool) bool {
+	return getTyped(p, key, defaultValue, cast.ToBoolE)
+}
+
+// GetTime returns the property as a time.Time, or defaultValue when it is
+// missing, nil or cannot be converted. Strings are parsed in the common
+// layouts (RFC 3339 and others) and numbers are read as Unix seconds.
+func (p *PropertyObject) GetTime(key string, defaultValue time.Time) time.Time {
+	return getTyped(p, key, defaultValue, cast.ToTimeE)
+}
+
+// GetSlice returns the property as a []any, or defaultValue when it is
+// missing, nil or not a slice. Typed slices such as []string are converted.
+func (p *PropertyObject) GetSlice(key string, defaultValue []any) []any {
+	return getTyped(p, key, defaultValue, toSliceE)
+}
+
+// GetMap returns the property as a map[string]any, or defaultValue when it
+// is missing, nil or cannot be converted. JSON object strings are decoded.
+func (p *PropertyObject) GetMap(key string, defaultValue map[string]any) map[string]any {
+	return getTyped(p, key, defaultValue, cast.ToStringMapE)
+}
+
+// getTyped reads key and converts it with convert, falling back to
+// defaultValue.
+func getTyped[T any](p *PropertyObject, key string, defaultValue T, convert func(any) (T, error)) T {
+	value := p.Get(key)
+	if value == nil {
+		return defaultValue
+	}
+
+	converted, err := convert(value)
+	if err != nil {
+		return defaultValue
+	}
+
+	return converted
+}
+
+// toIntE converts value with toInt64E, rejecting values out of the int
+// range.
+func toIntE(value any) (int, error) {
+	converted, err := toInt64E(value)
+	if err != nil {
+		return 0, err
+	}
+	if converted < math.MinInt || converted > math.MaxInt {
+		return 0, fmt.Errorf("value %d overflows int", converted)
+	}
+	return int(converted), nil
+}
+
+// toInt64E converts value to an int64 without the lossy conversions of
+// cast: strings are parsed in base 10 instead of detecting octal and hex
+// prefixes, and floats must be integral.
+func toInt64E(value any) (int64, error) {
+	switch typed := value.(type) {
+	case string:
+		return strconv.ParseInt(strings.TrimSpace(typed), 10, 64)
+	case json.Number:
+		if converted, err := typed.Int64(); err == nil {
+			return converted, nil
+		}
+		float, err := typed.Float64()
+		if err != nil {
+			return 0, err
+		}
+		return floatToInt64(float)
+	case float64:
+		return floatToInt64(typed)
+	case float32:
+		return floatToInt64(float64(typed))
+	}
+	return cast.ToInt64E(value)
+}
+
+// floatToInt64 converts an integral float to an int64.
+func floatToInt64(value float64) (int64, error) {
+	if value != math.Trunc(value) || value < math.MinInt64 || value >= math.MaxInt64 {
+		return 0, fmt.Errorf("value %v is not an int64", value)
+	}
+	return int64(value), nil
+}
+
+// toSliceE converts any slice or array to []any.
+func toSliceE(value any) ([]any, error) {
+	if slice, err := cast.ToSliceE(value); err == nil {
+		return slice, nil
+	}
+
+	reflected := reflect.ValueOf(value)
+	if reflected.Kind() != reflect.Slice && reflected.Kind() != reflect.Array {
+		return cast.ToSliceE(value)
+	}
+
+	slice := make([]any, reflected.Len())
+	for i := range slice {
+		slice[i] = reflected.Index(i).Interface()
+	}
+	return slice, nil
+}
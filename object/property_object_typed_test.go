@@ -0,0 +1,168 @@
+package object_test
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/dracory/base/object"
+)
+
+func Test_PropertyObject_TypedGetters(t *testing.T) {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	po := object.NewPropertyObject().(*object.PropertyObject)
+	po.Set("name", "John")
+	po.Set("age", "42")
+	po.Set("count", float64(7))
+	po.Set("big", int64(9007199254740993))
+	po.Set("ratio", "0.5")
+	po.Set("active", "true")
+	po.Set("created", "2025-01-02T03:04:05Z")
+	po.Set("tags", []string{"a", "b"})
+	po.Set("settings", map[string]any{"theme": "dark"})
+	po.Set("nothing", nil)
+
+	if got := po.GetString("name", ""); got != "John" {
+		t.Errorf("GetString = %q", got)
+	}
+	if got := po.GetString("count", ""); got != "7" {
+		t.Errorf("GetString(number) = %q, want 7", got)
+	}
+	if got := po.GetInt("age", 0); got != 42 {
+		t.Errorf("GetInt = %d, want 42", got)
+	}
+	if got := po.GetInt("count", 0); got != 7 {
+		t.Errorf("GetInt(float64) = %d, want 7", got)
+	}
+	if got := po.GetInt64("big", 0); got != 9007199254740993 {
+		t.Errorf("GetInt64 = %d", got)
+	}
+	if got := po.GetFloat("ratio", 0); got != 0.5 {
+		t.Errorf("GetFloat = %v, want 0.5", got)
+	}
+	if got := po.GetBool("active", false); !got {
+		t.Error("GetBool = false, want true")
+	}
+	if got := po.GetTime("created", time.Time{}); !got.Equal(created) {
+		t.Errorf("GetTime = %v, want %v", got, created)
+	}
+	if got := po.GetSlice("tags", nil); !reflect.DeepEqual(got, []any{"a", "b"}) {
+		t.Errorf("GetSlice = %v", got)
+	}
+	if got := po.GetMap("settings", nil); got["theme"] != "dark" {
+		t.Errorf("GetMap = %v", got)
+	}
+
+	// Missing, nil and unconvertible values fall back to the default
+	if got := po.GetString("missing", "default"); got != "default" {
+		t.Errorf("GetString(missing) = %q", got)
+	}
+	if got := po.GetInt("nothing", -1); got != -1 {
+		t.Errorf("GetInt(nil) = %d", got)
+	}
+	if got := po.GetInt("name", -1); got != -1 {
+		t.Errorf("GetInt(non-numeric) = %d", got)
+	}
+	for _, value := range []any{7.9, "7.9", "1e3", float64(math.MaxInt64) * 2} {
+		po.Set("lossy", value)
+		if got := po.GetInt("lossy", -1); got != -1 {
+			t.Errorf("GetInt(%v) = %d, want the default", value, got)
+		}
+	}
+	po.Set("padded", "010")
+	if got := po.GetInt("padded", 0); got != 10 {
+		t.Errorf(`GetInt("010") = %d, want 10`, got)
+	}
+	po.Set("integral", json.Number("12.0"))
+	if got := po.GetInt64("integral", 0); got != 12 {
+		t.Errorf(`GetInt64(json.Number("12.0")) = %d, want 12`, got)
+	}
+	if got := po.GetBool("name", true); !got {
+		t.Error("GetBool(non-boolean) should return the default")
+	}
+	if got := po.GetTime("name", created); !got.Equal(created) {
+		t.Errorf("GetTime(invalid) = %v", got)
+	}
+	if got := po.GetSlice("name", []any{"x"}); !reflect.DeepEqual(got, []any{"x"}) {
+		t.Errorf("GetSlice(non-slice) = %v", got)
+	}
+	if got := po.GetMap("age", nil); got != nil {
+		t.Errorf("GetMap(non-map) = %v", got)
+	}
+}
+
+func TestSerializablePropertyObject_FromJSONWithNumbers(t *testing.T) {
+	spo := object.NewSerializablePropertyObject().(*object.SerializablePropertyObject)
+	spo.Set("big", int64(9007199254740993))
+	spo.Set("price", 19.99)
+
+	data, err := spo.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+
+	lossy := object.NewSerializablePropertyObject().(*object.SerializablePropertyObject)
+	if err := lossy.FromJSON(data); err != nil {
+		t.Fatalf("FromJSON failed: %v", err)
+	}
+	if lossy.GetInt64("big", 0) == 9007199254740993 {
+		t.Error("FromJSON is expected to lose precision on large integers")
+	}
+
+	exact := object.NewSerializablePropertyObject().(*object.SerializablePropertyObject)
+	if err := exact.FromJSONWithNumbers(data); err != nil {
+		t.Fatalf("FromJSONWithNumbers failed: %v", err)
+	}
+	if _, ok := exact.Get("big").(json.Number); !ok {
+		t.Errorf("big = %T, want json.Number", exact.Get("big"))
+	}
+	if got := exact.GetInt64("big", 0); got != 9007199254740993 {
+		t.Errorf("GetInt64 = %d, want 9007199254740993", got)
+	}
+	if got := exact.GetFloat("price", 0); got != 19.99 {
+		t.Errorf("GetFloat = %v, want 19.99", got)
+	}
+
+	again, err := exact.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	if !json.Valid(again) || !reflect.DeepEqual(decodeNumbers(t, again), decodeNumbers(t, data)) {
+		t.Errorf("round trip changed the data: %s != %s", again, data)
+	}
+
+	if err := exact.FromJSONWithNumbers([]byte("invalid json")); err == nil {
+		t.Error("FromJSONWithNumbers should return an error for invalid JSON")
+	}
+}
+
+func TestSerializablePropertyObject_GetIDNonString(t *testing.T) {
+	spo := object.NewSerializablePropertyObject().(*object.SerializablePropertyObject)
+	if err := spo.FromJSON([]byte(`{"id": 42}`)); err != nil {
+		t.Fatalf("FromJSON failed: %v", err)
+	}
+	if got := spo.GetID(); got != "42" {
+		t.Errorf("GetID() = %q, want 42", got)
+	}
+
+	spo.Set("id", []string{"not", "an", "id"})
+	if got := spo.GetID(); got != "" {
+		t.Errorf("GetID() = %q, want empty string", got)
+	}
+}
+
+func decodeNumbers(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+	values := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		t.Fatal(err)
+	}
+	result := map[string]string{}
+	for k, v := range values {
+		result[k] = string(v)
+	}
+	return result
+}
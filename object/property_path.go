@@ -0,0 +1,275 @@
+package object
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is one step of a property path: a map key or a slice index.
+type pathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// String returns the segment as written in a path.
+func (s pathSegment) String() string {
+	if s.isIndex {
+		return "[" + strconv.Itoa(s.index) + "]"
+	}
+	return s.key
+}
+
+// parsePath splits a path such as "billing.address.city" or
+// "items[2].name" into segments. The path must start with a key.
+func parsePath(path string) ([]pathSegment, error) {
+	if path == "" {
+		return nil, errors.New("object: path cannot be empty")
+	}
+
+	segments := []pathSegment{}
+	for _, part := range strings.Split(path, ".") {
+		key, rest, hasIndex := strings.Cut(part, "[")
+		if key == "" {
+			return nil, fmt.Errorf("object: path %q has an empty key", path)
+		}
+		segments = append(segments, pathSegment{key: key})
+
+		for hasIndex {
+			raw, after, closed := strings.Cut(rest, "]")
+			if !closed {
+				return nil, fmt.Errorf("object: path %q has an unclosed index", path)
+			}
+
+			index, err := strconv.Atoi(raw)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("object: path %q has an invalid index %q", path, raw)
+			}
+			segments = append(segments, pathSegment{index: index, isIndex: true})
+
+			if after == "" {
+				break
+			}
+			if !strings.HasPrefix(after, "[") {
+				return nil, fmt.Errorf("object: path %q has unexpected %q after an index", path, after)
+			}
+			rest = after[1:]
+		}
+	}
+
+	return segments, nil
+}
+
+// lookupPath walks segments starting at root.
+func lookupPath(root map[string]any, segments []pathSegment) (any, bool) {
+	var current any = root
+
+	for _, segment := range segments {
+		if segment.isIndex {
+			list, ok := current.([]any)
+			if !ok || segment.index >= len(list) {
+				return nil, false
+			}
+			current = list[segment.index]
+			continue
+		}
+
+		node, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current, ok = node[segment.key]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// setPath returns a copy of container with value stored at segments below
+// it. Every map and slice along the path is copied rather than modified, so
+// values previously handed out by Get or passed to Set never change. Missing
+// maps and slices are created; slices are padded with nil up to the index
+// being written.
+func setPath(container any, segments []pathSegment, value any) (any, error) {
+	if len(segments) == 0 {
+		return value, nil
+	}
+
+	segment, rest := segments[0], segments[1:]
+
+	if segment.isIndex {
+		var existing []any
+		switch v := container.(type) {
+		case nil:
+		case []any:
+			existing = v
+		default:
+			return nil, fmt.Errorf("object: cannot index %T with %s", container, segment)
+		}
+
+		list := make([]any, max(len(existing), segment.index+1))
+		copy(list, existing)
+
+		child, err := setPath(list[segment.index], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		list[segment.index] = child
+		return list, nil
+	}
+
+	var existing map[string]any
+	switch v := container.(type) {
+	case nil:
+	case map[string]any:
+		existing = v
+	default:
+		return nil, fmt.Errorf("object: cannot set key %q on %T", segment.key, container)
+	}
+
+	node := maps.Clone(existing)
+	if node == nil {
+		node = map[string]any{}
+	}
+
+	child, err := setPath(node[segment.key], rest, value)
+	if err != nil {
+		return nil, err
+	}
+	node[segment.key] = child
+	return node, nil
+}
+
+// unsetPath returns a copy of container without the value at segments
+// below it, copying the maps and slices along the path like setPath.
+// Removing a slice element shifts the elements after it.
+func unsetPath(container any, segments []pathSegment) any {
+	segment, rest := segments[0], segments[1:]
+
+	if segment.isIndex {
+		list, ok := container.([]any)
+		if !ok || segment.index >= len(list) {
+			return container
+		}
+		if len(rest) == 0 {
+			return slices.Delete(slices.Clone(list), segment.index, segment.index+1)
+		}
+		list = slices.Clone(list)
+		list[segment.index] = unsetPath(list[segment.index], rest)
+		return list
+	}
+
+	node, ok := container.(map[string]any)
+	if !ok {
+		return container
+	}
+	child, exists := node[segment.key]
+	if !exists {
+		return container
+	}
+	node = maps.Clone(node)
+	if len(rest) == 0 {
+		delete(node, segment.key)
+		return node
+	}
+	node[segment.key] = unsetPath(child, rest)
+	return node
+}
+
+// GetPath retrieves a nested value by path, e.g. "billing.address.city" or
+// "tags[2]". Returns nil when any step of the path does not exist.
+func (p *PropertyObject) GetPath(path string) any {
+	value, _ := p.lookup(path)
+	return value
+}
+
+// HasPath checks if a nested value exists at path.
+func (p *PropertyObject) HasPath(path string) bool {
+	_, exists := p.lookup(path)
+	return exists
+}
+
+// SetPath stores a nested value at path, creating intermediate maps and
+// slices as needed. It fails when the path is malformed, crosses an
+// existing value that is neither a map nor a slice, or the resulting
+// top-level property does not match the attached schema. The maps and
+// slices along the path are copied rather than modified in place, so values
+// returned earlier by Get or passed to Set never change. Observers are
+// notified under the top-level key with its old and new values, as for Set.
+func (p *PropertyObject) SetPath(path string, value any) error {
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	p.mutex.Lock()
+	if p.properties == nil {
+		p.properties = make(map[string]any)
+	}
+
+	// the new top-level value is built from copies, so it can be checked
+	// against the schema before anything is modified
+	top := segments[0].key
+	updated, err := setPath(p.properties[top], segments[1:], value)
+	if err == nil {
+		err = p.checkLocked(top, updated)
+	}
+	if err != nil {
+		p.mutex.Unlock()
+		return err
+	}
+
+	changes, observers := p.pendingChange(top, p.properties[top], updated)
+	p.properties[top] = updated
+	p.mutex.Unlock()
+
+	notify(changes, observers)
+	return nil
+}
+
+// UnsetPath removes the nested value at path. Removing a slice element
+// shifts the following elements down. Like SetPath, it copies the maps and
+// slices along the path and notifies observers under the top-level key.
+func (p *PropertyObject) UnsetPath(path string) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return
+	}
+
+	p.mutex.Lock()
+	var changes []propertyChange
+	var observers []propertyObserver
+	if _, exists := lookupPath(p.properties, segments); exists {
+		top := segments[0].key
+		old := p.properties[top]
+		if len(segments) == 1 {
+			delete(p.properties, top)
+			changes, observers = p.pendingChange(top, old, nil)
+		} else {
+			p.properties[top] = unsetPath(old, segments[1:])
+			changes, observers = p.pendingChange(top, old, p.properties[top])
+		}
+	}
+	p.mutex.Unlock()
+
+	notify(changes, observers)
+}
+
+// lookup parses path and walks it under the read lock.
+func (p *PropertyObject) lookup(path string) (any, bool) {
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, false
+	}
+
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return lookupPath(p.properties, segments)
+}
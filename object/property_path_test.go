@@ -0,0 +1,197 @@
+package object_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dracory/base/object"
+)
+
+func Test_PropertyObject_GetPath(t *testing.T) {
+	spo := object.NewSerializablePropertyObject().(*object.SerializablePropertyObject)
+	err := spo.FromJSON([]byte(`{
+		"billing": {"address": {"city": "Sofia"}},
+		"tags": ["a", "b", "c"],
+		"items": [{"name": "first"}, {"name": "second"}],
+		"matrix": [[1, 2], [3, 4]],
+		"flat.key": "flat"
+	}`))
+	if err != nil {
+		t.Fatalf("FromJSON failed: %v", err)
+	}
+
+	tests := []struct {
+		path   string
+		want   any
+		exists bool
+	}{
+		{"billing.address.city", "Sofia", true},
+		{"billing.address", map[string]any{"city": "Sofia"}, true},
+		{"tags[2]", "c", true},
+		{"items[1].name", "second", true},
+		{"matrix[1][0]", float64(3), true},
+		{"billing.address.zip", nil, false},
+		{"tags[3]", nil, false},
+		{"tags.name", nil, false},
+		{"billing[0]", nil, false},
+		{"flat.key", nil, false},
+		{"tags[x]", nil, false},
+		{"", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := spo.GetPath(tt.path); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+			if got := spo.HasPath(tt.path); got != tt.exists {
+				t.Errorf("HasPath(%q) = %v, want %v", tt.path, got, tt.exists)
+			}
+		})
+	}
+
+	// The flat API keeps treating dotted keys literally
+	if spo.Get("flat.key") != "flat" {
+		t.Errorf("Get(flat.key) = %v, want flat", spo.Get("flat.key"))
+	}
+}
+
+func Test_PropertyObject_SetPath(t *testing.T) {
+	po := object.NewPropertyObject().(*object.PropertyObject)
+
+	if err := po.SetPath("billing.address.city", "Sofia"); err != nil {
+		t.Fatalf("SetPath failed: %v", err)
+	}
+	if err := po.SetPath("tags[2]", "x"); err != nil {
+		t.Fatalf("SetPath failed: %v", err)
+	}
+	if err := po.SetPath("items[0].name", "first"); err != nil {
+		t.Fatalf("SetPath failed: %v", err)
+	}
+	if err := po.SetPath("billing.address.zip", "1000"); err != nil {
+		t.Fatalf("SetPath failed: %v", err)
+	}
+
+	wantBilling := map[string]any{"address": map[string]any{"city": "Sofia", "zip": "1000"}}
+	if got := po.Get("billing"); !reflect.DeepEqual(got, wantBilling) {
+		t.Errorf("billing = %v, want %v", got, wantBilling)
+	}
+	if got := po.Get("tags"); !reflect.DeepEqual(got, []any{nil, nil, "x"}) {
+		t.Errorf("tags = %v, want [<nil> <nil> x]", got)
+	}
+	if got := po.GetPath("items[0].name"); got != "first" {
+		t.Errorf("items[0].name = %v, want first", got)
+	}
+
+	for _, path := range []string{"billing.address.city.name", "billing[0]", "tags[-1]", "tags[1", "a..b", "[0]"} {
+		if err := po.SetPath(path, "value"); err == nil {
+			t.Errorf("SetPath(%q) should fail", path)
+		}
+	}
+
+	if got := po.GetPath("billing.address.city"); got != "Sofia" {
+		t.Errorf("failed SetPath must not modify existing values, city = %v", got)
+	}
+	if po.Has("a") {
+		t.Error("failed SetPath must not create intermediate maps")
+	}
+}
+
+func Test_PropertyObject_UnsetPath(t *testing.T) {
+	po := object.NewPropertyObject().(*object.PropertyObject)
+	po.Set("tags", []any{"a", "b", "c"})
+	po.SetPath("billing.address.city", "Sofia")
+	po.SetPath("billing.address.zip", "1000")
+
+	po.UnsetPath("tags[1]")
+	if got := po.Get("tags"); !reflect.DeepEqual(got, []any{"a", "c"}) {
+		t.Errorf("tags = %v, want [a c]", got)
+	}
+
+	po.UnsetPath("billing.address.zip")
+	if po.HasPath("billing.address.zip") || !po.HasPath("billing.address.city") {
+		t.Errorf("billing = %v, want only the zip removed", po.Get("billing"))
+	}
+
+	// Missing and malformed paths are ignored
+	po.UnsetPath("billing.missing.key")
+	po.UnsetPath("tags[9]")
+	po.UnsetPath("tags[")
+	if po.Count() != 2 {
+		t.Errorf("Count() = %d, want 2", po.Count())
+	}
+}
+
+func Test_PropertyObject_PathConcurrentOperations(t *testing.T) {
+	po := object.NewPropertyObject().(*object.PropertyObject)
+
+	const numGoroutines = 10
+	done := make(chan bool, numGoroutines)
+
+	for i := range numGoroutines {
+		go func(id int) {
+			for j := 0; j < 100; j++ {
+				po.SetPath("shared.counter", j)
+				po.SetPath("list[5]", id)
+				po.GetPath("shared.counter")
+				po.HasPath("list[5]")
+				if j%10 == 0 {
+					po.UnsetPath("list[0]")
+				}
+			}
+			done <- true
+		}(i)
+	}
+
+	for range numGoroutines {
+		<-done
+	}
+}
+
+func Test_PropertyObject_SetPathCopiesOnWrite(t *testing.T) {
+	po := object.NewPropertyObject().(*object.PropertyObject)
+	original := map[string]any{"x": 1, "list": []any{"a", "b"}}
+	po.Set("a", original)
+
+	held := po.Get("a").(map[string]any)
+
+	if err := po.SetPath("a.y", 2); err != nil {
+		t.Fatalf("SetPath failed: %v", err)
+	}
+	if err := po.SetPath("a.list[0]", "z"); err != nil {
+		t.Fatalf("SetPath failed: %v", err)
+	}
+	po.UnsetPath("a.x")
+
+	if _, changed := original["y"]; changed || original["x"] != 1 || original["list"].([]any)[0] != "a" {
+		t.Errorf("SetPath modified the map passed to Set: %v", original)
+	}
+	if _, changed := held["y"]; changed {
+		t.Errorf("SetPath modified the map returned by Get: %v", held)
+	}
+
+	if po.GetPath("a.y") != 2 || po.GetPath("a.list[0]") != "z" || po.HasPath("a.x") {
+		t.Errorf("unexpected object state: %v", po.Get("a"))
+	}
+}
+
+func Test_PropertyObject_PathConcurrentReaders(t *testing.T) {
+	po := object.NewPropertyObject().(*object.PropertyObject)
+	po.Set("a", map[string]any{"n": 0})
+
+	done := make(chan bool)
+	go func() {
+		for i := range 200 {
+			po.SetPath("a.n", i)
+		}
+		done <- true
+	}()
+
+	for range 200 {
+		// reading a map returned by Get must not race with SetPath
+		for key, value := range po.Get("a").(map[string]any) {
+			_, _ = key, value
+		}
+	}
+	<-done
+}
@@ -0,0 +1,357 @@
+package object
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Property types understood by PropertySchema.
+const (
+	PropertyTypeAny    = ""
+	PropertyTypeString = "string"
+	PropertyTypeInt    = "int"
+	PropertyTypeFloat  = "float"
+	PropertyTypeBool   = "bool"
+	PropertyTypeTime   = "time"
+	PropertyTypeSlice  = "slice"
+	PropertyTypeMap    = "map"
+)
+
+// PropertySpec declares one property accepted by a PropertySchema.
+type PropertySpec struct {
+	// Key is the property key
+	Key string
+
+	// Type is one of the PropertyType* constants; PropertyTypeAny accepts
+	// every value. Numbers decoded from JSON (float64, json.Number) are
+	// accepted as int when they are whole
+	Type string
+
+	// Required marks properties that must be set to a non-nil value
+	Required bool
+
+	// Default is set by SetSchema when the property is missing, optional
+	Default any
+
+	// Enum lists the allowed values, if restricted. Values are compared by
+	// their JSON form, so 1 matches 1.0
+	Enum []any
+
+	// Min and Max bound numbers, and the length of strings, slices and
+	// maps, optional
+	Min *float64
+	Max *float64
+}
+
+// Bound returns a pointer to value, for use as PropertySpec.Min or Max.
+func Bound(value float64) *float64 {
+	return &value
+}
+
+// PropertyError describes a property that does not match its schema.
+type PropertyError struct {
+	Key    string
+	Value  any
+	Reason string
+}
+
+// Error returns the formatted error describing the property.
+func (e *PropertyError) Error() string {
+	return fmt.Sprintf("object: property %q %s", e.Key, e.Reason)
+}
+
+// PropertySchema declares the properties an object may hold. Attach it to
+// an object with SetSchema.
+type PropertySchema struct {
+	specs []PropertySpec
+
+	// AllowUnknown accepts keys that are not declared
+	AllowUnknown bool
+}
+
+// NewPropertySchema creates a schema from specs. Keys must be non-blank and
+// unique, and types must be known.
+func NewPropertySchema(specs ...PropertySpec) (*PropertySchema, error) {
+	schema := &PropertySchema{}
+	for _, spec := range specs {
+		if strings.TrimSpace(spec.Key) == "" {
+			return nil, errors.New("object: schema key cannot be empty")
+		}
+		if _, exists := schema.Spec(spec.Key); exists {
+			return nil, fmt.Errorf("object: schema key %q is declared twice", spec.Key)
+		}
+		if !slices.Contains(propertyTypes, spec.Type) {
+			return nil, fmt.Errorf("object: schema key %q has unknown type %q", spec.Key, spec.Type)
+		}
+		if spec.Default != nil {
+			if err := spec.check(spec.Default); err != nil {
+				return nil, fmt.Errorf("object: schema key %q has an invalid default: %w", spec.Key, err)
+			}
+		}
+		schema.specs = append(schema.specs, spec)
+	}
+	return schema, nil
+}
+
+// propertyTypes lists the known property types.
+var propertyTypes = []string{
+	PropertyTypeAny,
+	PropertyTypeString,
+	PropertyTypeInt,
+	PropertyTypeFloat,
+	PropertyTypeBool,
+	PropertyTypeTime,
+	PropertyTypeSlice,
+	PropertyTypeMap,
+}
+
+// Specs returns the specs in declaration order.
+func (s *PropertySchema) Specs() []PropertySpec {
+	return append([]PropertySpec(nil), s.specs...)
+}
+
+// Spec returns the spec declared for key.
+func (s *PropertySchema) Spec(key string) (PropertySpec, bool) {
+	for _, spec := range s.specs {
+		if spec.Key == key {
+			return spec, true
+		}
+	}
+	return PropertySpec{}, false
+}
+
+// Check returns a *PropertyError when value may not be set under key.
+func (s *PropertySchema) Check(key string, value any) error {
+	spec, declared := s.Spec(key)
+	if !declared {
+		if s.AllowUnknown {
+			return nil
+		}
+		return &PropertyError{Key: key, Value: value, Reason: "is not declared in the schema"}
+	}
+
+	if value == nil {
+		if spec.Required {
+			return &PropertyError{Key: key, Reason: "is required"}
+		}
+		return nil
+	}
+
+	return spec.check(value)
+}
+
+// Validate checks every property in properties and that the required ones
+// are present.
+//
+// Returns:
+//   - error: the *PropertyError values joined with errors.Join, or nil
+func (s *PropertySchema) Validate(properties map[string]any) error {
+	return s.validate(properties, "")
+}
+
+// validate is Validate, additionally accepting implicitKey when it is not
+// declared.
+func (s *PropertySchema) validate(properties map[string]any, implicitKey string) error {
+	errs := []error{}
+
+	for _, spec := range s.specs {
+		if value, exists := properties[spec.Key]; spec.Required && (!exists || value == nil) {
+			errs = append(errs, &PropertyError{Key: spec.Key, Reason: "is required"})
+		}
+	}
+
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		_, declared := s.Spec(key)
+		if declared && properties[key] == nil {
+			continue // reported above when required
+		}
+		if !declared && implicitKey != "" && key == implicitKey {
+			continue
+		}
+		if err := s.Check(key, properties[key]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// check validates value, which is not nil, against the spec.
+func (spec PropertySpec) check(value any) error {
+	invalid := func(reason string) error {
+		return &PropertyError{Key: spec.Key, Value: value, Reason: reason}
+	}
+
+	size, sized := 0.0, false
+
+	switch spec.Type {
+	case PropertyTypeString:
+		text, ok := value.(string)
+		if !ok {
+			return invalid(fmt.Sprintf("must be a string, got %T", value))
+		}
+		size, sized = float64(utf8.RuneCountInString(text)), true
+	case PropertyTypeInt:
+		number, ok := propertyNumber(value)
+		if !ok || number != math.Trunc(number) {
+			return invalid(fmt.Sprintf("must be an integer, got %v", value))
+		}
+		size, sized = number, true
+	case PropertyTypeFloat:
+		number, ok := propertyNumber(value)
+		if !ok {
+			return invalid(fmt.Sprintf("must be a number, got %T", value))
+		}
+		size, sized = number, true
+	case PropertyTypeBool:
+		if _, ok := value.(bool); !ok {
+			return invalid(fmt.Sprintf("must be a boolean, got %T", value))
+		}
+	case PropertyTypeTime:
+		if !isPropertyTime(value) {
+			return invalid("must be a time.Time or an RFC 3339 string")
+		}
+	case PropertyTypeSlice:
+		reflected := reflect.ValueOf(value)
+		if reflected.Kind() != reflect.Slice && reflected.Kind() != reflect.Array {
+			return invalid(fmt.Sprintf("must be a list, got %T", value))
+		}
+		size, sized = float64(reflected.Len()), true
+	case PropertyTypeMap:
+		reflected := reflect.ValueOf(value)
+		if reflected.Kind() != reflect.Map || reflected.Type().Key().Kind() != reflect.String {
+			return invalid(fmt.Sprintf("must be an object, got %T", value))
+		}
+		size, sized = float64(reflected.Len()), true
+	}
+
+	if len(spec.Enum) > 0 && !slices.ContainsFunc(spec.Enum, func(allowed any) bool { return jsonEqual(allowed, value) }) {
+		return invalid(fmt.Sprintf("must be one of %v, got %v", spec.Enum, value))
+	}
+
+	if sized {
+		measure := "must be"
+		if spec.Type == PropertyTypeString || spec.Type == PropertyTypeSlice || spec.Type == PropertyTypeMap {
+			measure = "must have a length of"
+		}
+		if spec.Min != nil && size < *spec.Min {
+			return invalid(fmt.Sprintf("%s at least %v", measure, *spec.Min))
+		}
+		if spec.Max != nil && size > *spec.Max {
+			return invalid(fmt.Sprintf("%s at most %v", measure, *spec.Max))
+		}
+	}
+
+	return nil
+}
+
+// propertyNumber converts the Go and JSON number types to float64.
+func propertyNumber(value any) (float64, bool) {
+	if number, ok := value.(json.Number); ok {
+		converted, err := number.Float64()
+		return converted, err == nil
+	}
+
+	reflected := reflect.ValueOf(value)
+	switch reflected.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflected.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(reflected.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return reflected.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// isPropertyTime reports whether value is a time.Time or an RFC 3339
+// string, the form time.Time takes in JSON.
+func isPropertyTime(value any) bool {
+	switch v := value.(type) {
+	case time.Time:
+		return true
+	case string:
+		_, err := time.Parse(time.RFC3339Nano, v)
+		return err == nil
+	default:
+		return false
+	}
+}
+
+// SetSchema attaches schema to the object, or detaches it when nil. Once
+// attached, Set and SetPath reject values that do not match the schema, and
+// missing properties with a default are set to it. Values already present
+// are not checked; use Validate.
+func (p *PropertyObject) SetSchema(schema *PropertySchema) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.schema = schema
+	if schema == nil {
+		return
+	}
+
+	if p.properties == nil {
+		p.properties = make(map[string]any)
+	}
+	for _, spec := range schema.specs {
+		if _, exists := p.properties[spec.Key]; !exists && spec.Default != nil {
+			p.properties[spec.Key] = cloneValue(spec.Default)
+		}
+	}
+}
+
+// Schema returns the attached schema, or nil.
+func (p *PropertyObject) Schema() *PropertySchema {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.schema
+}
+
+// Validate checks all properties against the attached schema, e.g. after
+// FromJSON. It returns nil when no schema is attached.
+//
+// Returns:
+//   - error: the *PropertyError values joined with errors.Join, or nil
+func (p *PropertyObject) Validate() error {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	return p.validateLocked(p.properties)
+}
+
+// checkLocked checks value against the attached schema, accepting the
+// undeclared ID key. The caller must hold the lock.
+func (p *PropertyObject) checkLocked(key string, value any) error {
+	if p.schema == nil {
+		return nil
+	}
+	if _, declared := p.schema.Spec(key); !declared && p.idKey != "" && key == p.idKey {
+		return nil
+	}
+	return p.schema.Check(key, value)
+}
+
+// validateLocked validates properties against the attached schema,
+// accepting the undeclared ID key. The caller must hold the lock.
+func (p *PropertyObject) validateLocked(properties map[string]any) error {
+	if p.schema == nil {
+		return nil
+	}
+	return p.schema.validate(properties, p.idKey)
+}
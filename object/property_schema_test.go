@@ -0,0 +1,195 @@
+package object_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dracory/base/object"
+)
+
+func newUserSchema(t *testing.T) *object.PropertySchema {
+	t.Helper()
+	schema, err := object.NewPropertySchema(
+		object.PropertySpec{Key: "id", Type: object.PropertyTypeString, Required: true},
+		object.PropertySpec{Key: "name", Type: object.PropertyTypeString, Required: true, Min: object.Bound(2), Max: object.Bound(20)},
+		object.PropertySpec{Key: "age", Type: object.PropertyTypeInt, Min: object.Bound(0), Max: object.Bound(150)},
+		object.PropertySpec{Key: "score", Type: object.PropertyTypeFloat},
+		object.PropertySpec{Key: "active", Type: object.PropertyTypeBool, Default: true},
+		object.PropertySpec{Key: "role", Type: object.PropertyTypeString, Enum: []any{"admin", "user"}, Default: "user"},
+		object.PropertySpec{Key: "created", Type: object.PropertyTypeTime},
+		object.PropertySpec{Key: "tags", Type: object.PropertyTypeSlice, Max: object.Bound(2)},
+		object.PropertySpec{Key: "address", Type: object.PropertyTypeMap},
+		object.PropertySpec{Key: "extra"},
+	)
+	if err != nil {
+		t.Fatalf("NewPropertySchema failed: %v", err)
+	}
+	return schema
+}
+
+func TestNewPropertySchema_Errors(t *testing.T) {
+	tests := map[string][]object.PropertySpec{
+		"empty key":       {{Key: " "}},
+		"duplicate key":   {{Key: "a"}, {Key: "a"}},
+		"unknown type":    {{Key: "a", Type: "uuid"}},
+		"invalid default": {{Key: "a", Type: object.PropertyTypeInt, Default: "ten"}},
+	}
+	for name, specs := range tests {
+		if _, err := object.NewPropertySchema(specs...); err == nil {
+			t.Errorf("%s: NewPropertySchema should fail", name)
+		}
+	}
+}
+
+func Test_PropertyObject_SetWithSchema(t *testing.T) {
+	spo := object.NewSerializablePropertyObject().(*object.SerializablePropertyObject)
+	spo.SetSchema(newUserSchema(t))
+
+	if spo.Get("active") != true || spo.Get("role") != "user" {
+		t.Errorf("defaults not applied: active=%v role=%v", spo.Get("active"), spo.Get("role"))
+	}
+
+	valid := map[string]any{
+		"name":    "John",
+		"age":     42,
+		"score":   9.5,
+		"created": time.Now(),
+		"tags":    []string{"a", "b"},
+		"address": map[string]any{"city": "Sofia"},
+		"extra":   struct{}{},
+		"role":    "admin",
+	}
+	for key, value := range valid {
+		if err := spo.Set(key, value); err != nil {
+			t.Errorf("Set(%s) unexpected error: %v", key, err)
+		}
+	}
+	if err := spo.Set("age", float64(43)); err != nil {
+		t.Errorf("whole float64 should be accepted as int: %v", err)
+	}
+	if err := spo.Set("created", "2025-01-02T03:04:05Z"); err != nil {
+		t.Errorf("RFC 3339 string should be accepted as time: %v", err)
+	}
+
+	invalid := []struct {
+		key    string
+		value  any
+		reason string
+	}{
+		{"nmae", "typo", "is not declared in the schema"},
+		{"name", 42, "must be a string, got int"},
+		{"name", "J", "must have a length of at least 2"},
+		{"age", "old", "must be an integer, got old"},
+		{"age", 4.5, "must be an integer, got 4.5"},
+		{"age", 151, "must be at most 150"},
+		{"age", -1, "must be at least 0"},
+		{"score", "high", "must be a number, got string"},
+		{"active", "yes", "must be a boolean, got string"},
+		{"role", "root", "must be one of [admin user], got root"},
+		{"created", "yesterday", "must be a time.Time or an RFC 3339 string"},
+		{"tags", "a", "must be a list, got string"},
+		{"tags", []any{1, 2, 3}, "must have a length of at most 2"},
+		{"address", "Sofia", "must be an object, got string"},
+		{"name", nil, "is required"},
+	}
+	for _, tt := range invalid {
+		before := spo.Get(tt.key)
+		err := spo.Set(tt.key, tt.value)
+
+		var propertyErr *object.PropertyError
+		if !errors.As(err, &propertyErr) {
+			t.Errorf("Set(%s, %v) error = %v, want *PropertyError", tt.key, tt.value, err)
+			continue
+		}
+		if propertyErr.Key != tt.key || propertyErr.Reason != tt.reason {
+			t.Errorf("Set(%s, %v) = %q, want reason %q", tt.key, tt.value, err, tt.reason)
+		}
+		if spo.Get(tt.key) != nil && before == nil {
+			t.Errorf("rejected Set(%s) modified the object", tt.key)
+		}
+	}
+
+	if err := spo.SetPath("address.city", "Plovdiv"); err != nil {
+		t.Errorf("SetPath within a map property failed: %v", err)
+	}
+	if err := spo.SetPath("tags[5]", "x"); err == nil {
+		t.Error("SetPath exceeding the max length should fail")
+	}
+	if err := spo.SetPath("unknown.key", "x"); err == nil || spo.Has("unknown") {
+		t.Error("SetPath on an undeclared key should fail without writing")
+	}
+
+	spo.SetSchema(nil)
+	if err := spo.Set("anything", 1); err != nil {
+		t.Errorf("Set without schema failed: %v", err)
+	}
+}
+
+func Test_PropertyObject_ValidateSchema(t *testing.T) {
+	spo := object.NewSerializablePropertyObject().(*object.SerializablePropertyObject)
+	if err := spo.Validate(); err != nil {
+		t.Errorf("Validate without schema = %v, want nil", err)
+	}
+
+	err := spo.FromJSON([]byte(`{"id": "1", "age": 200, "role": "root", "nmae": "typo", "score": null}`))
+	if err != nil {
+		t.Fatalf("FromJSON failed: %v", err)
+	}
+	spo.SetSchema(newUserSchema(t))
+
+	err = spo.Validate()
+	if err == nil {
+		t.Fatal("Validate should fail")
+	}
+
+	message := err.Error()
+	for _, want := range []string{
+		`object: property "name" is required`,
+		`object: property "age" must be at most 150`,
+		`object: property "role" must be one of [admin user], got root`,
+		`object: property "nmae" is not declared in the schema`,
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("Validate() missing %q:\n%s", want, message)
+		}
+	}
+	if strings.Contains(message, "score") {
+		t.Errorf("null optional property should be accepted:\n%s", message)
+	}
+
+	schema := newUserSchema(t)
+	schema.AllowUnknown = true
+	spo.FromJSON([]byte(`{"id": "1", "name": "John", "age": 30, "custom": 1}`))
+	spo.SetSchema(schema)
+	if err := spo.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+}
+
+func Test_SerializablePropertyObject_SetIDWithSchema(t *testing.T) {
+	schema, err := object.NewPropertySchema(
+		object.PropertySpec{Key: "name", Type: object.PropertyTypeString},
+	)
+	if err != nil {
+		t.Fatalf("NewPropertySchema failed: %v", err)
+	}
+
+	spo := object.NewSerializablePropertyObject().(*object.SerializablePropertyObject)
+	spo.SetSchema(schema)
+
+	spo.SetID("user-1")
+	if spo.GetID() != "user-1" {
+		t.Errorf("GetID() = %q, want %q", spo.GetID(), "user-1")
+	}
+	if err := spo.Validate(); err != nil {
+		t.Errorf("Validate() = %v, undeclared id should be accepted", err)
+	}
+
+	po := object.NewPropertyObject().(*object.PropertyObject)
+	po.SetSchema(schema)
+	if err := po.Set("id", "1"); err == nil {
+		t.Error("plain property objects should not accept an undeclared id")
+	}
+}
@@ -1,6 +1,7 @@
 package object
 
 import (
+	"bytes"
 	"encoding/json"
 
 	"github.com/google/uuid"
@@ -13,22 +14,31 @@ type SerializablePropertyObject struct {
 
 // NewSerializablePropertyObject creates a new SerializablePropertyObject with a generated UUID
 func NewSerializablePropertyObject() SerializablePropertyObjectInterface {
+	return newSerializablePropertyObject()
+}
+
+// newSerializablePropertyObject creates the concrete object returned by
+// NewSerializablePropertyObject.
+func newSerializablePropertyObject() *SerializablePropertyObject {
 	return &SerializablePropertyObject{
-		PropertyObject: PropertyObject{properties: map[string]any{
-			"id": uuid.New().String(),
-		}},
+		PropertyObject: PropertyObject{
+			properties: map[string]any{
+				"id": uuid.New().String(),
+			},
+			idKey: "id",
+		},
 	}
 }
 
-// GetID returns the unique identifier for this object
+// GetID returns the unique identifier for this object. Non-string IDs, such
+// as numbers decoded from JSON, are converted to strings.
 func (s *SerializablePropertyObject) GetID() string {
-	if s.Has("id") {
-		return s.Get("id").(string)
-	}
-	return ""
+	return s.GetString("id", "")
 }
 
-// SetID sets the unique identifier for this object
+// SetID sets the unique identifier for this object. An attached schema
+// always accepts "id", even when it does not declare it; if the schema does
+// declare "id", an ID not matching that spec is not set.
 func (s *SerializablePropertyObject) SetID(id string) {
 	if id == "" {
 		return
@@ -38,6 +48,8 @@ func (s *SerializablePropertyObject) SetID(id string) {
 
 // ToJSON serializes the SerializablePropertyObject to JSON
 func (s *SerializablePropertyObject) ToJSON() ([]byte, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
 	return json.Marshal(s.properties)
 }
 
@@ -50,6 +62,28 @@ func (s *SerializablePropertyObject) FromJSON(data []byte) error {
 		return err
 	}
 
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.properties = temp
+
+	return nil
+}
+
+// FromJSONWithNumbers deserializes JSON data like FromJSON, but decodes
+// numbers as json.Number instead of float64, so large integers survive a
+// round trip through ToJSON. Use the typed getters, e.g. GetInt64, to read
+// the numbers.
+func (s *SerializablePropertyObject) FromJSONWithNumbers(data []byte) error {
+	temp := map[string]any{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&temp); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	s.properties = temp
 
 	return nil
@@ -0,0 +1,89 @@
+package object
+
+import (
+	"errors"
+	"fmt"
+)
+
+// VersionAny disables the version check of StoreInterface.Save, so the
+// object is written whatever version is stored.
+const VersionAny int64 = -1
+
+// ErrNotFound is returned when no object is stored under an ID.
+var ErrNotFound = errors.New("object: not found")
+
+// ErrVersionConflict is matched, via errors.Is, by the VersionConflictError
+// returned when a save is based on an outdated version.
+var ErrVersionConflict = errors.New("object: version conflict")
+
+// VersionConflictError describes a save whose expected version does not
+// match the stored one, usually because another writer saved the object
+// first.
+type VersionConflictError struct {
+	ID       string
+	Expected int64
+	Actual   int64
+}
+
+// Error returns the formatted error describing the conflict.
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("object: version conflict for %q: expected version %d, stored version is %d", e.ID, e.Expected, e.Actual)
+}
+
+// Unwrap allows errors.Is(err, ErrVersionConflict).
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
+
+// StoreInterface persists serializable objects by ID with optimistic
+// locking. Every stored object has a version, starting at 1 and incremented
+// by each save.
+type StoreInterface interface {
+	// Save stores object under its ID. version is the version the caller
+	// last read, 0 when creating a new object, or VersionAny to skip the
+	// check. It returns the new version, or a *VersionConflictError when
+	// version is outdated.
+	Save(object SerializableInterface, version int64) (int64, error)
+
+	// Find loads the object stored under id together with its version, or
+	// returns ErrNotFound.
+	Find(id string) (SerializablePropertyObjectInterface, int64, error)
+
+	// Delete removes the object stored under id, or returns ErrNotFound.
+	Delete(id string) error
+
+	// List returns the sorted IDs starting with prefix; an empty prefix
+	// lists all IDs.
+	List(prefix string) ([]string, error)
+}
+
+// nextVersion checks expected against the stored version, where 0 means
+// the object does not exist, and returns the version to store.
+func nextVersion(id string, expected, stored int64) (int64, error) {
+	if expected != VersionAny && expected != stored {
+		return 0, &VersionConflictError{ID: id, Expected: expected, Actual: stored}
+	}
+	return stored + 1, nil
+}
+
+// storedObject decodes data, as written by ToJSON, into a new object.
+// Numbers are kept as json.Number so integers survive the round trip.
+func storedObject(data []byte) (SerializablePropertyObjectInterface, error) {
+	object := newSerializablePropertyObject()
+	if err := object.FromJSONWithNumbers(data); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// objectID returns the ID of object, which must not be empty.
+func objectID(object SerializableInterface) (string, error) {
+	if object == nil {
+		return "", errors.New("object: cannot save a nil object")
+	}
+	id := object.GetID()
+	if id == "" {
+		return "", errors.New("object: cannot save an object without an ID")
+	}
+	return id, nil
+}
@@ -0,0 +1,172 @@
+package object
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// fileStoreExtension is the extension of the files written by the file
+// store.
+const fileStoreExtension = ".json"
+
+// fileStore is a StoreInterface keeping one JSON file per object in a
+// directory.
+type fileStore struct {
+	mutex     sync.Mutex
+	directory string
+}
+
+// fileRecord is the content of a file written by the file store.
+type fileRecord struct {
+	Version int64           `json:"version"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// NewFileStore creates a store keeping each object in
+// "<directory>/<escaped id>.json", creating directory if needed. Files are
+// replaced atomically. Version checks are serialized within the process;
+// processes sharing a directory are not coordinated.
+//
+// Returns:
+//   - StoreInterface: the store
+//   - error: If the directory cannot be created
+func NewFileStore(directory string) (StoreInterface, error) {
+	if strings.TrimSpace(directory) == "" {
+		return nil, errors.New("object: file store directory cannot be empty")
+	}
+	if err := os.MkdirAll(directory, 0o755); err != nil {
+		return nil, fmt.Errorf("object: create file store directory: %w", err)
+	}
+	return &fileStore{directory: directory}, nil
+}
+
+// Save stores object under its ID, see StoreInterface.Save.
+func (s *fileStore) Save(object SerializableInterface, version int64) (int64, error) {
+	id, err := objectID(object)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := object.ToJSON()
+	if err != nil {
+		return 0, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	stored := int64(0)
+	record, err := s.read(id)
+	switch {
+	case err == nil:
+		stored = record.Version
+	case !errors.Is(err, ErrNotFound):
+		return 0, err
+	}
+
+	next, err := nextVersion(id, version, stored)
+	if err != nil {
+		return 0, err
+	}
+
+	content, err := json.Marshal(fileRecord{Version: next, Data: data})
+	if err != nil {
+		return 0, err
+	}
+
+	path := s.path(id)
+	temp := path + ".tmp"
+	if err := os.WriteFile(temp, content, 0o644); err != nil {
+		return 0, fmt.Errorf("object: write %q: %w", id, err)
+	}
+	if err := os.Rename(temp, path); err != nil {
+		os.Remove(temp)
+		return 0, fmt.Errorf("object: write %q: %w", id, err)
+	}
+
+	return next, nil
+}
+
+// Find loads the object stored under id, see StoreInterface.Find.
+func (s *fileStore) Find(id string) (SerializablePropertyObjectInterface, int64, error) {
+	s.mutex.Lock()
+	record, err := s.read(id)
+	s.mutex.Unlock()
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	object, err := storedObject(record.Data)
+	if err != nil {
+		return nil, 0, fmt.Errorf("object: decode %q: %w", id, err)
+	}
+	return object, record.Version, nil
+}
+
+// Delete removes the object stored under id, see StoreInterface.Delete.
+func (s *fileStore) Delete(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := os.Remove(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// List returns the sorted IDs starting with prefix.
+func (s *fileStore) List(prefix string) ([]string, error) {
+	entries, err := os.ReadDir(s.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, fileStoreExtension) {
+			continue
+		}
+
+		id, err := url.PathUnescape(strings.TrimSuffix(name, fileStoreExtension))
+		if err != nil || !strings.HasPrefix(id, prefix) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// read loads the record stored under id. The caller must hold the mutex.
+func (s *fileStore) read(id string) (fileRecord, error) {
+	content, err := os.ReadFile(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return fileRecord{}, ErrNotFound
+	}
+	if err != nil {
+		return fileRecord{}, err
+	}
+
+	record := fileRecord{}
+	if err := json.Unmarshal(content, &record); err != nil {
+		return fileRecord{}, fmt.Errorf("object: decode %q: %w", id, err)
+	}
+	return record, nil
+}
+
+// path returns the file holding id. IDs are escaped so they cannot leave
+// the directory.
+func (s *fileStore) path(id string) string {
+	return filepath.Join(s.directory, url.PathEscape(id)+fileStoreExtension)
+}
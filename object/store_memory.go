@@ -0,0 +1,93 @@
+package object
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// memoryStore is an in-memory StoreInterface, useful for tests and caches.
+type memoryStore struct {
+	mutex   sync.RWMutex
+	records map[string]memoryRecord
+}
+
+// memoryRecord is a stored object in its serialized form.
+type memoryRecord struct {
+	data    []byte
+	version int64
+}
+
+// NewMemoryStore creates an empty in-memory store. Objects are stored
+// serialized, so later changes to a saved object do not affect the store.
+func NewMemoryStore() StoreInterface {
+	return &memoryStore{records: map[string]memoryRecord{}}
+}
+
+// Save stores object under its ID, see StoreInterface.Save.
+func (s *memoryStore) Save(object SerializableInterface, version int64) (int64, error) {
+	id, err := objectID(object)
+	if err != nil {
+		return 0, err
+	}
+
+	data, err := object.ToJSON()
+	if err != nil {
+		return 0, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	next, err := nextVersion(id, version, s.records[id].version)
+	if err != nil {
+		return 0, err
+	}
+
+	s.records[id] = memoryRecord{data: data, version: next}
+	return next, nil
+}
+
+// Find loads the object stored under id, see StoreInterface.Find.
+func (s *memoryStore) Find(id string) (SerializablePropertyObjectInterface, int64, error) {
+	s.mutex.RLock()
+	record, exists := s.records[id]
+	s.mutex.RUnlock()
+
+	if !exists {
+		return nil, 0, ErrNotFound
+	}
+
+	object, err := storedObject(record.data)
+	if err != nil {
+		return nil, 0, err
+	}
+	return object, record.version, nil
+}
+
+// Delete removes the object stored under id, see StoreInterface.Delete.
+func (s *memoryStore) Delete(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, exists := s.records[id]; !exists {
+		return ErrNotFound
+	}
+	delete(s.records, id)
+	return nil
+}
+
+// List returns the sorted IDs starting with prefix.
+func (s *memoryStore) List(prefix string) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ids := []string{}
+	for id := range s.records {
+		if strings.HasPrefix(id, prefix) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
@@ -0,0 +1,156 @@
+package object_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dracory/base/object"
+)
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, object.NewMemoryStore())
+}
+
+func TestFileStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "objects")
+	store, err := object.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+
+	testStore(t, store)
+
+	// IDs are escaped and cannot leave the directory
+	entity := object.NewSerializablePropertyObject().(*object.SerializablePropertyObject)
+	entity.SetID("../escape/attempt")
+	if _, err := store.Save(entity, 0); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "..%2Fescape%2Fattempt.json")); err != nil {
+		t.Errorf("expected escaped file name: %v", err)
+	}
+	if ids, _ := store.List("../"); !reflect.DeepEqual(ids, []string{"../escape/attempt"}) {
+		t.Errorf("List() = %v", ids)
+	}
+
+	if _, err := object.NewFileStore(" "); err == nil {
+		t.Error("NewFileStore with an empty directory should fail")
+	}
+}
+
+func testStore(t *testing.T, store object.StoreInterface) {
+	t.Helper()
+
+	user := object.NewSerializablePropertyObject().(*object.SerializablePropertyObject)
+	user.SetID("user:1")
+	user.Set("name", "John")
+	user.Set("visits", int64(9007199254740993))
+
+	version, err := store.Save(user, 0)
+	if err != nil || version != 1 {
+		t.Fatalf("Save(new) = %d, %v; want 1, nil", version, err)
+	}
+
+	// Creating the same ID again conflicts
+	var conflict *object.VersionConflictError
+	if _, err := store.Save(user, 0); !errors.As(err, &conflict) || conflict.Actual != 1 || !errors.Is(err, object.ErrVersionConflict) {
+		t.Errorf("Save(existing, 0) error = %v, want version conflict", err)
+	}
+
+	found, foundVersion, err := store.Find("user:1")
+	if err != nil || foundVersion != 1 {
+		t.Fatalf("Find() = %d, %v", foundVersion, err)
+	}
+	typed := found.(object.TypedGetterInterface)
+	if typed.GetString("name", "") != "John" || typed.GetInt64("visits", 0) != 9007199254740993 || found.GetID() != "user:1" {
+		t.Errorf("Find() returned %v, %v, %v", found.Get("name"), found.Get("visits"), found.GetID())
+	}
+
+	// Two writers based on the same version: the second one conflicts
+	first, _, _ := store.Find("user:1")
+	second, _, _ := store.Find("user:1")
+	first.Set("name", "First")
+	second.Set("name", "Second")
+
+	if version, err := store.Save(first, 1); err != nil || version != 2 {
+		t.Fatalf("Save(first) = %d, %v; want 2, nil", version, err)
+	}
+	if _, err := store.Save(second, 1); !errors.Is(err, object.ErrVersionConflict) {
+		t.Errorf("Save(second) error = %v, want ErrVersionConflict", err)
+	}
+	if latest, _, _ := store.Find("user:1"); latest.Get("name") != "First" {
+		t.Errorf("conflicting save overwrote the object: %v", latest.Get("name"))
+	}
+	if version, err := store.Save(second, object.VersionAny); err != nil || version != 3 {
+		t.Errorf("Save(VersionAny) = %d, %v; want 3, nil", version, err)
+	}
+
+	// Saved objects are copies
+	user.Set("name", "Changed after save")
+	if latest, _, _ := store.Find("user:1"); latest.Get("name") != "Second" {
+		t.Errorf("store should not see changes made after saving, got %v", latest.Get("name"))
+	}
+
+	for _, id := range []string{"user:2", "order:1"} {
+		entity := object.NewSerializablePropertyObject().(*object.SerializablePropertyObject)
+		entity.SetID(id)
+		if _, err := store.Save(entity, 0); err != nil {
+			t.Fatalf("Save(%s) failed: %v", id, err)
+		}
+	}
+
+	if ids, err := store.List("user:"); err != nil || !reflect.DeepEqual(ids, []string{"user:1", "user:2"}) {
+		t.Errorf("List(user:) = %v, %v", ids, err)
+	}
+
+	if err := store.Delete("user:2"); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+	if err := store.Delete("user:2"); !errors.Is(err, object.ErrNotFound) {
+		t.Errorf("Delete(missing) error = %v, want ErrNotFound", err)
+	}
+	if _, _, err := store.Find("user:2"); !errors.Is(err, object.ErrNotFound) {
+		t.Errorf("Find(deleted) error = %v, want ErrNotFound", err)
+	}
+
+	// Deleting resets the version, so the ID can be created again
+	recreated := object.NewSerializablePropertyObject().(*object.SerializablePropertyObject)
+	recreated.SetID("user:2")
+	if version, err := store.Save(recreated, 0); err != nil || version != 1 {
+		t.Errorf("Save(recreated) = %d, %v; want 1, nil", version, err)
+	}
+
+	noID := object.NewSerializablePropertyObject().(*object.SerializablePropertyObject)
+	noID.Unset("id")
+	if _, err := store.Save(noID, 0); err == nil {
+		t.Error("Save without an ID should fail")
+	}
+
+	// Concurrent saves of the same version: exactly one wins
+	var wins atomic.Int32
+	var wg sync.WaitGroup
+	_, current, _ := store.Find("order:1")
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			entity, _, err := store.Find("order:1")
+			if err != nil {
+				t.Errorf("Find failed: %v", err)
+				return
+			}
+			if _, err := store.Save(entity, current); err == nil {
+				wins.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if wins.Load() != 1 {
+		t.Errorf("%d concurrent saves succeeded, want 1", wins.Load())
+	}
+}